@@ -23,8 +23,8 @@ func buildContainer(cmd *cobra.Command) (*action.Container, error) {
 	}
 
 	fs := io.CreateOsFs()
-	opts := config.CreateContextOpts(cmd)
-	logger := io.NewLogger(os.Stdout, opts.EnableColors, opts.Debug)
+	opts := createContextOpts(cmd)
+	logger := io.NewLogger(os.Stdout, os.Stderr, opts.EnableColors, opts.Debug)
 	gitAdapter := io.NewGoGitAdapter()
 	context, err := config.NewContext(fs, logger, opts)
 	if err != nil {
@@ -42,6 +42,13 @@ func buildContainer(cmd *cobra.Command) (*action.Container, error) {
 	return container, err
 }
 
+// createContextOpts - returns the options for the command's context, including the build version
+func createContextOpts(cmd *cobra.Command) config.ContextOpts {
+	opts := config.CreateContextOpts(cmd)
+	opts.Version = Version
+	return opts
+}
+
 func init() {
 	containers = make(map[*cobra.Command]*action.Container)
 }
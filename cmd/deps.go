@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+)
+
+func newDepsCmd(containerFunc ContainerBuilderFunc) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deps <workflow-name>...",
+		Short: "Lists the files the given workflows are generated from, including imported files",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return errors.New("at least one workflow name required")
+			}
+
+			container, err := containerFunc(cmd)
+			if err != nil {
+				return err
+			}
+
+			dependencies, err := container.WorkflowManager().GetWorkflowDependencies(args)
+			if err != nil {
+				return err
+			}
+			for _, path := range dependencies {
+				container.Logger().Println(path)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
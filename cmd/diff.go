@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/jbrunton/gflows/workflow"
+	"github.com/jbrunton/gflows/workflow/action"
+	"github.com/spf13/cobra"
+)
+
+func newDiffCmd(containerFunc ContainerBuilderFunc) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <base-ref> [<head-ref>]",
+		Short: "Show how the generated workflows differ between two revisions (or a revision and the working directory)",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			summaryOnly, err := cmd.Flags().GetBool("summary")
+			if err != nil {
+				return err
+			}
+
+			container, err := containerFunc(cmd)
+			if err != nil {
+				return err
+			}
+
+			base, err := action.GetRevisionDefinitions(container, args[0], createContextOpts(cmd))
+			if err != nil {
+				return err
+			}
+
+			var head []*workflow.Definition
+			if len(args) > 1 {
+				head, err = action.GetRevisionDefinitions(container, args[1], createContextOpts(cmd))
+			} else {
+				head, err = container.WorkflowManager().GetWorkflowDefinitions()
+			}
+			if err != nil {
+				return err
+			}
+
+			changes := action.CompareWorkflowDefinitions(base, head)
+			if !summaryOnly {
+				patch := action.FormatWorkflowChangesPatch(changes)
+				if patch != "" {
+					container.Logger().PrettyPrintDiff(patch)
+				}
+			}
+			container.Logger().Printf("%s", action.FormatWorkflowChangesSummary(changes))
+
+			for _, change := range changes {
+				if change.Type == action.WorkflowInvalid {
+					return errors.New("errors encountered generating workflows")
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().Bool("summary", false, "only print the summary of added, removed and changed workflows")
+	return cmd
+}
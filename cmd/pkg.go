@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jbrunton/gflows/workflow/action"
+	"github.com/spf13/cobra"
+	"github.com/thoas/go-funk"
+)
+
+func newPkgCmd(containerFunc ContainerBuilderFunc) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pkg",
+		Short: "Manage the packages the templates depend on",
+	}
+	cmd.AddCommand(newPkgUpdateCmd(containerFunc))
+	cmd.AddCommand(newPkgTreeCmd(containerFunc))
+	cmd.AddCommand(newPkgManifestCmd(containerFunc))
+	return cmd
+}
+
+func newPkgUpdateCmd(containerFunc ContainerBuilderFunc) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update [<package-name>...]",
+		Short: "Reinstalls the given packages (or all packages) and records them in the lock file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			container, err := containerFunc(cmd)
+			if err != nil {
+				return err
+			}
+			return container.Environment().UpdateLock(args)
+		},
+	}
+	return cmd
+}
+
+func newPkgTreeCmd(containerFunc ContainerBuilderFunc) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tree",
+		Short: "Prints the packages the templates depend on, and the packages they depend on in turn",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			container, err := containerFunc(cmd)
+			if err != nil {
+				return err
+			}
+			dependencies := funk.UniqString(container.Context().Config.GetAllDependencies())
+			for _, path := range dependencies {
+				err := printPackageTree(container, path, 0)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+func printPackageTree(container *action.Container, path string, depth int) error {
+	lib, err := container.Environment().LoadDependency(path)
+	if err != nil {
+		return err
+	}
+	source := lib.Path
+	if lib.Commit != "" {
+		source = fmt.Sprintf("%s, %s", source, shortCommit(lib.Commit))
+	}
+	container.Logger().Printfln("%s%s (%s)", strings.Repeat("  ", depth), lib.PackageName, source)
+	for _, depPath := range lib.Dependencies {
+		err := printPackageTree(container, depPath, depth+1)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newPkgManifestCmd(containerFunc ContainerBuilderFunc) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "manifest [<package-dir>]",
+		Short: "Lists the files in the libs/ and workflows/ directories of a package in its gflowspkg.json, for packages hosted over HTTP",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 1 {
+				return errors.New("at most one package directory expected")
+			}
+			dir := "."
+			if len(args) == 1 {
+				dir = args[0]
+			}
+
+			container, err := containerFunc(cmd)
+			if err != nil {
+				return err
+			}
+			return container.Installer().UpdateManifest(dir)
+		},
+	}
+	return cmd
+}
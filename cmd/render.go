@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jbrunton/gflows/workflow/action"
+	"github.com/spf13/cobra"
+	"github.com/thoas/go-funk"
+)
+
+func newRenderCmd(containerFunc ContainerBuilderFunc) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "render <workflow-name>...",
+		Short: "Prints the generated content for the given workflows without writing any files",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return errors.New("at least one workflow name required")
+			}
+
+			output, err := cmd.Flags().GetString("output")
+			if err != nil {
+				return err
+			}
+			if !funk.ContainsString(action.RenderFormats, output) {
+				return fmt.Errorf("Unexpected output: %q, valid options are %s", output, strings.Join(action.RenderFormats, ", "))
+			}
+
+			noHeader, err := cmd.Flags().GetBool("no-header")
+			if err != nil {
+				return err
+			}
+
+			container, err := containerFunc(cmd)
+			if err != nil {
+				return err
+			}
+
+			content, err := container.WorkflowManager().RenderWorkflows(args, !noHeader, output)
+			if err != nil {
+				return err
+			}
+			container.Logger().Printf("%s", content)
+			return nil
+		},
+	}
+	cmd.Flags().String("output", "yaml", "output format (yaml or json)")
+	cmd.Flags().Bool("no-header", false, "omit the \"File generated by gflows\" header")
+	return cmd
+}
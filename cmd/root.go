@@ -65,13 +65,23 @@ func NewRootCommand(containerFunc ContainerBuilderFunc) *cobra.Command {
 	cmd.PersistentFlags().StringP("config", "c", "", "Location of config file")
 	cmd.PersistentFlags().Bool("disable-colors", false, "Disable colors in output")
 	cmd.PersistentFlags().BoolP("debug", "d", false, "Print debug information")
+	cmd.PersistentFlags().Bool("refresh-schemas", false, "Download remote schemas even if they are cached")
+	cmd.PersistentFlags().Bool("no-cache", false, "Don't read or write the build and package caches")
+	cmd.PersistentFlags().Bool("offline", false, "Only use cached packages and schemas, failing if they aren't cached")
+	cmd.PersistentFlags().IntP("jobs", "j", 0, "Number of templates to evaluate in parallel (defaults to the number of CPUs)")
 
 	cmd.AddCommand(newListWorkflowsCmd(containerFunc))
 	cmd.AddCommand(newUpdateWorkflowsCmd(containerFunc))
+	cmd.AddCommand(newPruneWorkflowsCmd(containerFunc))
+	cmd.AddCommand(newRenderCmd(containerFunc))
+	cmd.AddCommand(newDepsCmd(containerFunc))
+	cmd.AddCommand(newPkgCmd(containerFunc))
+	cmd.AddCommand(newDiffCmd(containerFunc))
 	cmd.AddCommand(newCheckWorkflowsCmd(containerFunc))
 	cmd.AddCommand(newWatchWorkflowsCmd(containerFunc))
 	cmd.AddCommand(newImportWorkflowsCmd(containerFunc))
 	cmd.AddCommand(newInitCmd(containerFunc))
+	cmd.AddCommand(newSchemaCmd(containerFunc))
 	cmd.AddCommand(newVersionCmd(containerFunc))
 
 	return cmd
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"github.com/jbrunton/gflows/workflow"
+	"github.com/spf13/cobra"
+)
+
+func newSchemaCmd(containerFunc ContainerBuilderFunc) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Manage the GitHub workflow schema used to validate workflows",
+	}
+	cmd.AddCommand(newUpdateSchemaCmd(containerFunc))
+	return cmd
+}
+
+func newUpdateSchemaCmd(containerFunc ContainerBuilderFunc) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update",
+		Short: "Download the latest GitHub workflow schema and cache it in the context directory",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			uri, err := cmd.Flags().GetString("uri")
+			if err != nil {
+				return err
+			}
+
+			container, err := containerFunc(cmd)
+			if err != nil {
+				return err
+			}
+
+			return container.SchemaManager().UpdateSchema(uri)
+		},
+	}
+	cmd.Flags().String("uri", workflow.GitHubWorkflowSchemaURI, "the location of the schema to download")
+	return cmd
+}
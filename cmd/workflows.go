@@ -3,7 +3,10 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/jbrunton/gflows/env"
+	"github.com/jbrunton/gflows/workflow"
 	"github.com/jbrunton/gflows/workflow/action"
 	"github.com/olekukonko/tablewriter"
 	"github.com/thoas/go-funk"
@@ -12,26 +15,38 @@ import (
 )
 
 func newListWorkflowsCmd(containerFunc ContainerBuilderFunc) *cobra.Command {
-	return &cobra.Command{
-		Use:   "ls",
+	cmd := &cobra.Command{
+		Use:   "ls [<workflow-name>...]",
 		Short: "List workflows",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := getReportFormat(cmd, action.ReportFormats)
+			if err != nil {
+				return err
+			}
+
+			filter, err := getWorkflowFilter(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			container, err := containerFunc(cmd)
 			if err != nil {
 				return err
 			}
 
-			workflowManager := container.WorkflowManager()
-			definitions, err := workflowManager.GetWorkflowDefinitions()
+			reports, err := container.WorkflowManager().GetWorkflowReports(filter)
 			if err != nil {
 				return err
 			}
-			validator := container.Validator()
+
+			if format != "text" {
+				return printReports(container, reports, format)
+			}
 
 			table := tablewriter.NewWriter(container.Logger())
 			table.SetHeader([]string{"Name", "Source", "Target", "Status"})
 			context := container.Context()
-			for _, definition := range definitions {
+			for _, report := range reports {
 				colors := []tablewriter.Colors{
 					tablewriter.Colors{},
 					tablewriter.Colors{},
@@ -42,56 +57,139 @@ func newListWorkflowsCmd(containerFunc ContainerBuilderFunc) *cobra.Command {
 					colors[0] = tablewriter.Colors{tablewriter.FgGreenColor}
 					colors[1] = tablewriter.Colors{tablewriter.FgYellowColor}
 					colors[2] = tablewriter.Colors{tablewriter.FgYellowColor}
-				}
-				var status string
-				if !definition.Status.Valid {
-					status = "TEMPLATE ERROR"
-					if context.EnableColors {
-						colors[3] = tablewriter.Colors{tablewriter.FgRedColor}
-					}
-				} else if !validator.ValidateSchema(definition).Valid {
-					status = "INVALID SCHEMA"
-					if context.EnableColors {
-						colors[3] = tablewriter.Colors{tablewriter.FgRedColor}
-					}
-				} else if !validator.ValidateContent(definition).Valid {
-					status = "OUT OF DATE"
-					if context.EnableColors {
-						colors[3] = tablewriter.Colors{tablewriter.FgRedColor}
-					}
-				} else {
-					status = "UP TO DATE"
-					if context.EnableColors {
+					if report.Valid() {
 						colors[3] = tablewriter.Colors{tablewriter.FgGreenColor}
+					} else {
+						colors[3] = tablewriter.Colors{tablewriter.FgRedColor}
 					}
 				}
 
-				row := []string{definition.Name, definition.Description, definition.Destination, status}
+				source := report.Description
+				if report.Commit != "" {
+					source = fmt.Sprintf("%s (%s)", source, shortCommit(report.Commit))
+				}
+				row := []string{report.Name, source, report.Destination, report.Status.Description()}
 				table.Rich(row, colors)
 			}
 			table.Render()
+
+			logger := container.Logger()
+			for _, report := range reports {
+				errors := append(append([]string{}, report.TemplateErrors...), report.SchemaErrors...)
+				if len(errors) > 0 {
+					logger.Printfln("%s:", container.Styles().Bold(report.Name))
+					logger.PrintStatusErrors(errors, true)
+				}
+			}
 			return nil
 		},
 	}
+	cmd.Flags().String("format", "text", "output format (text, json or yaml)")
+	addWorkflowFilterFlags(cmd)
+	return cmd
 }
 
 func newUpdateWorkflowsCmd(containerFunc ContainerBuilderFunc) *cobra.Command {
-	return &cobra.Command{
-		Use:   "update",
+	cmd := &cobra.Command{
+		Use:   "update [<workflow-name>...]",
 		Short: "Updates workflow files",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			prune, err := cmd.Flags().GetBool("prune")
+			if err != nil {
+				return err
+			}
+
+			filter, err := getWorkflowFilter(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			dryRun, err := cmd.Flags().GetBool("dry-run")
+			if err != nil {
+				return err
+			}
+
+			partial, err := cmd.Flags().GetBool("partial")
+			if err != nil {
+				return err
+			}
+
+			patchFile, err := cmd.Flags().GetString("patch-file")
+			if err != nil {
+				return err
+			}
+			if patchFile != "" && !dryRun {
+				return errors.New("--patch-file requires --dry-run")
+			}
+
 			container, err := containerFunc(cmd)
 			if err != nil {
 				return err
 			}
 			workflowManager := container.WorkflowManager()
-			err = workflowManager.UpdateWorkflows()
+			if dryRun {
+				return diffWorkflows(workflowManager, container, filter, prune, partial, patchFile)
+			}
+
+			_, err = workflowManager.UpdateWorkflows(filter, partial)
+			if err == nil || partial {
+				lockErr := container.Environment().SaveLock()
+				if lockErr != nil {
+					return lockErr
+				}
+			}
+			if prune && (err == nil || partial) {
+				// with --partial, prune even if some workflows failed to update, since orphans are
+				// unaffected by errors
+				pruneErr := workflowManager.PruneWorkflows(filter)
+				if err == nil {
+					err = pruneErr
+				}
+			}
 			if err != nil {
 				return err
 			}
 			return nil
 		},
 	}
+	cmd.Flags().Bool("prune", false, "delete orphaned workflows (generated workflows whose templates no longer exist) selected by the filter")
+	cmd.Flags().Bool("partial", false, "update the valid workflows even if others have errors, rather than updating none")
+	cmd.Flags().Bool("dry-run", false, "print a diff of the changes (which git apply accepts) instead of writing them")
+	cmd.Flags().String("patch-file", "", "with --dry-run, write the diff to the given file instead of printing it")
+	addWorkflowFilterFlags(cmd)
+	return cmd
+}
+
+// diffWorkflows - prints (or writes to patchFile) the diff for a dry run of update
+func diffWorkflows(workflowManager *action.WorkflowManager, container *action.Container, filter *workflow.WorkflowFilter, prune bool, partial bool, patchFile string) error {
+	patch, diffErr := workflowManager.DiffWorkflows(filter, prune, partial)
+	if patch == "" && diffErr != nil {
+		return diffErr
+	}
+	if patchFile == "" {
+		container.Logger().Printf("%s", patch)
+		return diffErr
+	}
+	err := container.ContentWriter().SafelyWriteFile(patchFile, patch)
+	if err != nil {
+		return err
+	}
+	container.Logger().Printfln("Wrote patch to %s", patchFile)
+	return diffErr
+}
+
+func newPruneWorkflowsCmd(containerFunc ContainerBuilderFunc) *cobra.Command {
+	return &cobra.Command{
+		Use:   "prune",
+		Short: "Deletes orphaned workflows (generated workflows whose templates no longer exist)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			container, err := containerFunc(cmd)
+			if err != nil {
+				return err
+			}
+			return container.WorkflowManager().PruneWorkflows(nil)
+		},
+	}
 }
 
 func newInitCmd(containerFunc ContainerBuilderFunc) *cobra.Command {
@@ -136,8 +234,59 @@ func newInitCmd(containerFunc ContainerBuilderFunc) *cobra.Command {
 	return cmd
 }
 
-func checkWorkflows(workflowManager *action.WorkflowManager, container *action.Container, watch bool, showDiff bool) error {
-	err := workflowManager.ValidateWorkflows(showDiff)
+type checkOptions struct {
+	filter    *workflow.WorkflowFilter
+	format    string
+	diffMode  action.DiffMode
+	junitPath string
+	sarifPath string
+}
+
+func checkWorkflows(workflowManager *action.WorkflowManager, container *action.Container, opts checkOptions) error {
+	reports, err := workflowManager.GetWorkflowReports(opts.filter)
+	if err != nil {
+		return err
+	}
+	return reportWorkflows(workflowManager, container, reports, opts)
+}
+
+// checkWorkflowsOnChange - returns the onChange func for check --watch, which checks the workflows
+// and returns the definitions checked
+func checkWorkflowsOnChange(workflowManager *action.WorkflowManager, container *action.Container, opts checkOptions) action.OnChangeFunc {
+	return func() []*workflow.Definition {
+		reports, err := workflowManager.GetWorkflowReports(opts.filter)
+		if err != nil {
+			container.Logger().Println(container.Styles().StyleError(err.Error()))
+			return nil
+		}
+		// invalid workflows are described by the reports, so there's no need to print the error
+		reportWorkflows(workflowManager, container, reports, opts)
+		return action.GetReportDefinitions(reports)
+	}
+}
+
+// reportWorkflows - prints the reports (and writes any report files), returning an error if any
+// workflows are invalid
+func reportWorkflows(workflowManager *action.WorkflowManager, container *action.Container, reports []*action.WorkflowReport, opts checkOptions) error {
+	err := writeReportFiles(container, reports, opts)
+	if err != nil {
+		return err
+	}
+
+	if opts.format != "text" {
+		err = printReports(container, reports, opts.format)
+		if err != nil {
+			return err
+		}
+		for _, report := range reports {
+			if !report.Valid() {
+				return errors.New("workflow validation failed")
+			}
+		}
+		return nil
+	}
+
+	err = workflowManager.PrintWorkflowReports(reports, opts.diffMode)
 	if err != nil {
 		return err
 	}
@@ -145,12 +294,103 @@ func checkWorkflows(workflowManager *action.WorkflowManager, container *action.C
 	return nil
 }
 
+func writeReportFiles(container *action.Container, reports []*action.WorkflowReport, opts checkOptions) error {
+	if opts.junitPath != "" {
+		report, err := action.FormatJUnitReport(reports)
+		if err != nil {
+			return err
+		}
+		err = container.ContentWriter().SafelyWriteFile(opts.junitPath, report)
+		if err != nil {
+			return err
+		}
+	}
+	if opts.sarifPath != "" {
+		report, err := action.FormatSarifReport(reports, Version)
+		if err != nil {
+			return err
+		}
+		err = container.ContentWriter().SafelyWriteFile(opts.sarifPath, report)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func getReportFormat(cmd *cobra.Command, validFormats []string) (string, error) {
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return "", err
+	}
+	if !funk.ContainsString(validFormats, format) {
+		return "", fmt.Errorf("Unexpected format: %q, valid options are %s", format, strings.Join(validFormats, ", "))
+	}
+	return format, nil
+}
+
+func addDiffModeFlag(cmd *cobra.Command) {
+	cmd.Flags().String("diff-mode", "text", "how to show diffs (text, or semantic to list changes by path)")
+}
+
+// getDiffMode - returns the diff mode to use if --show-diffs is set, otherwise DiffModeNone
+func getDiffMode(cmd *cobra.Command) (action.DiffMode, error) {
+	showDiff, err := cmd.Flags().GetBool("show-diffs")
+	if err != nil {
+		return action.DiffModeNone, err
+	}
+	diffMode, err := getDiffModeFlag(cmd)
+	if err != nil || !showDiff {
+		return action.DiffModeNone, err
+	}
+	return diffMode, nil
+}
+
+func getDiffModeFlag(cmd *cobra.Command) (action.DiffMode, error) {
+	diffMode, err := cmd.Flags().GetString("diff-mode")
+	if err != nil {
+		return action.DiffModeNone, err
+	}
+	if !funk.ContainsString(action.DiffModes, diffMode) {
+		return action.DiffModeNone, fmt.Errorf("Unexpected diff mode: %q, valid options are %s", diffMode, strings.Join(action.DiffModes, ", "))
+	}
+	return action.DiffMode(diffMode), nil
+}
+
+func addWorkflowFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("exclude", []string{}, "names or glob patterns of workflows to exclude")
+}
+
+// getWorkflowFilter - returns a filter for the workflows named by the positional args (which may be
+// glob patterns) and the --exclude flag
+func getWorkflowFilter(cmd *cobra.Command, args []string) (*workflow.WorkflowFilter, error) {
+	excludes, err := cmd.Flags().GetStringSlice("exclude")
+	if err != nil {
+		return nil, err
+	}
+	return workflow.NewWorkflowFilter(args, excludes)
+}
+
+func printReports(container *action.Container, reports []*action.WorkflowReport, format string) error {
+	output, err := action.FormatReports(reports, format)
+	if err != nil {
+		return err
+	}
+	container.Logger().Printf("%s", output)
+	return nil
+}
+
 func newCheckWorkflowsCmd(containerFunc ContainerBuilderFunc) *cobra.Command {
 	var container *action.Container
 	cmd := &cobra.Command{
-		Use:   "check",
+		Use:   "check [<workflow-name>...]",
 		Short: "Check workflow files are up to date",
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			filter, err := getWorkflowFilter(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			container, err = containerFunc(cmd)
 			if err != nil {
 				return err
@@ -161,33 +401,77 @@ func newCheckWorkflowsCmd(containerFunc ContainerBuilderFunc) *cobra.Command {
 				return err
 			}
 
-			showDiff, err := cmd.Flags().GetBool("show-diffs")
+			format, err := getReportFormat(cmd, action.CheckFormats)
 			if err != nil {
 				return err
 			}
 
+			diffMode, err := getDiffMode(cmd)
+			if err != nil {
+				return err
+			}
+
+			junitPath, err := cmd.Flags().GetString("report-junit")
+			if err != nil {
+				return err
+			}
+
+			sarifPath, err := cmd.Flags().GetString("report-sarif")
+			if err != nil {
+				return err
+			}
+
+			opts := checkOptions{
+				filter:    filter,
+				format:    format,
+				diffMode:  diffMode,
+				junitPath: junitPath,
+				sarifPath: sarifPath,
+			}
+			// warn about dependencies which haven't been locked (but check doesn't save the lock)
+			container.Environment().LockMode = env.LockModeWarn
 			workflowManager := container.WorkflowManager()
 			if watch {
 				watcher := container.Watcher()
-				watcher.WatchWorkflows(func() {
-					checkWorkflows(workflowManager, container, watch, showDiff)
-				})
-			} else {
-				err = checkWorkflows(workflowManager, container, watch, showDiff)
+				return watcher.WatchWorkflows(checkWorkflowsOnChange(workflowManager, container, opts))
 			}
-			return err
+			return checkWorkflows(workflowManager, container, opts)
 		},
 	}
 	cmd.Flags().BoolP("watch", "w", false, "watch workflow templates for changes")
 	cmd.Flags().Bool("show-diffs", false, "show diff with generated workflow (useful when refactoring)")
+	addDiffModeFlag(cmd)
+	cmd.Flags().String("format", "text", "output format (text, json, yaml or github for GitHub Actions annotations)")
+	cmd.Flags().String("report-junit", "", "write a JUnit XML report to the given path")
+	cmd.Flags().String("report-sarif", "", "write a SARIF report to the given path")
+	addWorkflowFilterFlags(cmd)
 	return cmd
 }
 
 func newWatchWorkflowsCmd(containerFunc ContainerBuilderFunc) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "watch",
-		Short: "Alias for check --watch --show-diffs",
+		Use:   "watch [<workflow-name>...]",
+		Short: "Alias for check --watch --show-diffs, or watch and update workflows with --update",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			filter, err := getWorkflowFilter(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			diffMode, err := getDiffModeFlag(cmd)
+			if err != nil {
+				return err
+			}
+
+			update, err := cmd.Flags().GetBool("update")
+			if err != nil {
+				return err
+			}
+			if update && cmd.Flags().Changed("diff-mode") {
+				// updated workflows are written rather than diffed
+				return errors.New("--diff-mode can't be used with --update")
+			}
+
 			container, err := containerFunc(cmd)
 			if err != nil {
 				return err
@@ -195,12 +479,21 @@ func newWatchWorkflowsCmd(containerFunc ContainerBuilderFunc) *cobra.Command {
 
 			workflowManager := container.WorkflowManager()
 			watcher := container.Watcher()
-			watcher.WatchWorkflows(func() {
-				checkWorkflows(workflowManager, container, true, true)
+			if !update {
+				return watcher.WatchWorkflows(checkWorkflowsOnChange(workflowManager, container, checkOptions{filter: filter, format: "text", diffMode: diffMode}))
+			}
+			return watcher.WatchWorkflows(func() []*workflow.Definition {
+				definitions, err := workflowManager.UpdateWorkflows(filter, false)
+				if err != nil {
+					container.Logger().Println(container.Styles().StyleError(err.Error()))
+				}
+				return definitions
 			})
-			return nil
 		},
 	}
+	cmd.Flags().Bool("update", false, "update workflows on each change instead of checking them")
+	addWorkflowFilterFlags(cmd)
+	addDiffModeFlag(cmd)
 	return cmd
 }
 
@@ -219,3 +512,11 @@ func newImportWorkflowsCmd(containerFunc ContainerBuilderFunc) *cobra.Command {
 		},
 	}
 }
+
+// shortCommit - abbreviates a commit SHA for display
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
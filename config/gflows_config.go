@@ -29,7 +29,20 @@ type GFlowsConfig struct {
 	}
 }
 
+// Valid values for the keyOrder workflow config
+const (
+	// KeyOrderPreserve - keep keys in the order the template engine emits them
+	KeyOrderPreserve = "preserve"
+	// KeyOrderGitHub - reorder keys to follow GitHub's conventions (e.g. name, on, jobs)
+	KeyOrderGitHub = "github"
+)
+
 type GFlowsWorkflowConfig struct {
+	KeyOrder string `yaml:"keyOrder"`
+	Header   struct {
+		Template string
+		Hash     *bool
+	}
 	Checks struct {
 		Schema struct {
 			Enabled *bool
@@ -55,7 +68,6 @@ func LoadConfig(fs *afero.Afero, logger *io.Logger, opts ContextOpts) (config *G
 		} else {
 			config = &GFlowsConfig{}
 			config.Templates.Engine = opts.Engine
-			config.Workflows.Defaults.Checks.Schema.URI = "https://json.schemastore.org/github-workflow"
 		}
 		return
 	}
@@ -118,6 +130,20 @@ func (config *GFlowsConfig) GetAllLibs() []string {
 	return libs
 }
 
+// GetAllHeaderTemplates - returns the custom header templates configured for any workflow
+func (config *GFlowsConfig) GetAllHeaderTemplates() []string {
+	templates := []string{}
+	if config.Workflows.Defaults.Header.Template != "" {
+		templates = append(templates, config.Workflows.Defaults.Header.Template)
+	}
+	for _, override := range config.Workflows.Overrides {
+		if override.Header.Template != "" {
+			templates = append(templates, override.Header.Template)
+		}
+	}
+	return templates
+}
+
 func (config *GFlowsConfig) GetAllDependencies() []string {
 	deps := []string{}
 	deps = append(deps, config.Templates.Defaults.Dependencies...)
@@ -133,6 +159,18 @@ func (config *GFlowsConfig) GetTemplateLibs(workflowName string) []string {
 	})
 }
 
+// GetWorkflowKeyOrder - returns the configured key order for the workflow (KeyOrderPreserve if none
+// is configured)
+func (config *GFlowsConfig) GetWorkflowKeyOrder(workflowName string) string {
+	keyOrder := config.GetWorkflowStringProperty(workflowName, func(config *GFlowsWorkflowConfig) string {
+		return config.KeyOrder
+	})
+	if keyOrder == "" {
+		return KeyOrderPreserve
+	}
+	return keyOrder
+}
+
 func (config *GFlowsConfig) GetTemplateDeps(workflowName string) []string {
 	return config.GetTemplateArrayProperty(workflowName, func(config *GFlowsTemplateConfig) []string {
 		return config.Dependencies
@@ -146,9 +184,6 @@ func parseConfig(input []byte) (*GFlowsConfig, error) {
 		panic(err)
 	}
 
-	if config.Workflows.Defaults.Checks.Schema.URI == "" {
-		config.Workflows.Defaults.Checks.Schema.URI = "https://json.schemastore.org/github-workflow"
-	}
 	if config.Templates.Engine == "" {
 		return nil, errors.New("missing value for config: templates.engine")
 	}
@@ -117,6 +117,20 @@ func TestGetWorkflowBoolProperty(t *testing.T) {
 	}
 }
 
+func TestGetWorkflowKeyOrder(t *testing.T) {
+	config, _ := parseConfig([]byte(strings.Join([]string{
+		"templates:",
+		"  engine: ytt",
+		"workflows:",
+		"  overrides:",
+		"    my-workflow:",
+		"      keyOrder: github",
+	}, "\n")))
+
+	assert.Equal(t, KeyOrderPreserve, config.GetWorkflowKeyOrder("some-workflow"))
+	assert.Equal(t, KeyOrderGitHub, config.GetWorkflowKeyOrder("my-workflow"))
+}
+
 func TestValidateConfig(t *testing.T) {
 	scenarios := []struct {
 		description    string
@@ -156,6 +170,16 @@ func TestValidateConfig(t *testing.T) {
 			expectedError:  "invalid config",
 			expectedOutput: "Schema error: workflows.overrides.my-workflow: Additional property foo is not allowed\n",
 		},
+		{
+			description: "invalid key order",
+			config: strings.Join([]string{
+				"workflows:",
+				"  defaults:",
+				"    keyOrder: alphabetical",
+			}, "\n"),
+			expectedError:  "invalid config",
+			expectedOutput: "Schema error: workflows.defaults.keyOrder: workflows.defaults.keyOrder must be one of the following: \"preserve\", \"github\"\n",
+		},
 		{
 			description: "valid config",
 			config: strings.Join([]string{
@@ -168,6 +192,7 @@ func TestValidateConfig(t *testing.T) {
 				"      libs: [my-lib]",
 				"workflows:",
 				"  defaults:",
+				"    keyOrder: github",
 				"    checks:",
 				"      schema:",
 				"        enabled: true",
@@ -187,7 +212,7 @@ func TestValidateConfig(t *testing.T) {
 
 	for _, scenario := range scenarios {
 		out := new(bytes.Buffer)
-		logger := io.NewLogger(out, false, false)
+		logger := io.NewLogger(out, out, false, false)
 		err := validateConfig(scenario.config, logger)
 
 		if scenario.expectedError == "" {
@@ -230,3 +255,23 @@ func TestGetAllDependencies(t *testing.T) {
 
 	assert.Equal(t, []string{"my-lib", "my-other-lib"}, config.GetAllDependencies())
 }
+
+func TestGetAllHeaderTemplates(t *testing.T) {
+	config, _ := parseConfig([]byte(strings.Join([]string{
+		"templates:",
+		"  engine: ytt",
+		"workflows:",
+		"  defaults:",
+		"    header:",
+		"      template: '# Generated from {{ .Source }}'",
+		"  overrides:",
+		"    my-workflow:",
+		"      header:",
+		"        template: '# {{ .Workflow }} generated by gflows'",
+		"    other-workflow:",
+		"      header:",
+		"        hash: true",
+	}, "\n")))
+
+	assert.Equal(t, []string{"# Generated from {{ .Source }}", "# {{ .Workflow }} generated by gflows"}, config.GetAllHeaderTemplates())
+}
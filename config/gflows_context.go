@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 
 	"github.com/jbrunton/gflows/io"
@@ -17,11 +18,25 @@ import (
 
 // GFlowsContext - current command context
 type GFlowsContext struct {
-	Dir          string
-	ConfigPath   string
-	GitHubDir    string
-	Config       *GFlowsConfig
-	EnableColors bool
+	Dir            string
+	ConfigPath     string
+	GitHubDir      string
+	Config         *GFlowsConfig
+	EnableColors   bool
+	RefreshSchemas bool
+
+	// Version - the version of gflows, as given in generated workflow headers
+	Version string
+
+	// NoCache - if true then the build and package caches are neither read nor written
+	NoCache bool
+
+	// Offline - if true then packages and schemas are only read from the caches, and are errors if
+	// they aren't cached
+	Offline bool
+
+	// Jobs - the maximum number of templates to evaluate (or workflows to validate) in parallel
+	Jobs int
 }
 
 type ContextOpts struct {
@@ -30,6 +45,11 @@ type ContextOpts struct {
 	Debug          bool
 	Engine         string
 	AllowNoContext bool
+	RefreshSchemas bool
+	Version        string
+	NoCache        bool
+	Offline        bool
+	Jobs           int
 }
 
 func NewContext(fs *afero.Afero, logger *io.Logger, opts ContextOpts) (*GFlowsContext, error) {
@@ -48,12 +68,25 @@ func NewContext(fs *afero.Afero, logger *io.Logger, opts ContextOpts) (*GFlowsCo
 		githubDir = filepath.Join(filepath.Dir(contextDir), githubDir)
 	}
 
+	jobs := opts.Jobs
+	if jobs == 0 {
+		jobs = runtime.NumCPU()
+	}
+	if jobs < 0 {
+		return nil, fmt.Errorf("Invalid value for jobs: %d, must be at least 1", opts.Jobs)
+	}
+
 	context := &GFlowsContext{
-		Config:       config,
-		ConfigPath:   opts.ConfigPath,
-		GitHubDir:    githubDir,
-		Dir:          contextDir,
-		EnableColors: opts.EnableColors,
+		Config:         config,
+		ConfigPath:     opts.ConfigPath,
+		GitHubDir:      githubDir,
+		Dir:            contextDir,
+		EnableColors:   opts.EnableColors,
+		RefreshSchemas: opts.RefreshSchemas,
+		Version:        opts.Version,
+		NoCache:        opts.NoCache,
+		Offline:        opts.Offline,
+		Jobs:           jobs,
 	}
 
 	logger.Debugf("Creating context: %s\n", spew.Sdump(context))
@@ -97,7 +130,39 @@ func CreateContextOpts(cmd *cobra.Command) ContextOpts {
 		}
 	}
 
-	allowNoContext := funk.ContainsString([]string{"init", "version"}, cmd.Name())
+	var refreshSchemas bool
+	if cmd.Flags().Lookup("refresh-schemas") != nil {
+		refreshSchemas, err = cmd.Flags().GetBool("refresh-schemas")
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	var noCache bool
+	if cmd.Flags().Lookup("no-cache") != nil {
+		noCache, err = cmd.Flags().GetBool("no-cache")
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	var offline bool
+	if cmd.Flags().Lookup("offline") != nil {
+		offline, err = cmd.Flags().GetBool("offline")
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	var jobs int
+	if cmd.Flags().Lookup("jobs") != nil {
+		jobs, err = cmd.Flags().GetInt("jobs")
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	allowNoContext := funk.ContainsString([]string{"init", "version", "manifest"}, cmd.Name())
 
 	return ContextOpts{
 		ConfigPath:     configPath,
@@ -105,6 +170,10 @@ func CreateContextOpts(cmd *cobra.Command) ContextOpts {
 		Debug:          debug,
 		Engine:         engine,
 		AllowNoContext: allowNoContext,
+		RefreshSchemas: refreshSchemas,
+		NoCache:        noCache,
+		Offline:        offline,
+		Jobs:           jobs,
 	}
 }
 
@@ -116,6 +185,12 @@ func (context *GFlowsContext) LibsDir() string {
 	return filepath.Join(context.Dir, "/libs")
 }
 
+// SchemaCachePath - returns the location of the cached copy of the GitHub workflow schema (as
+// written by "gflows schema update")
+func (context *GFlowsContext) SchemaCachePath() string {
+	return filepath.Join(context.Dir, "/schemas/github-workflow.json")
+}
+
 func (context *GFlowsContext) GetPathInfo(localPath string) (*pkg.PathInfo, error) {
 	relPath, err := filepath.Rel(context.Dir, localPath)
 	if err != nil {
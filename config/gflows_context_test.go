@@ -3,6 +3,7 @@ package config
 import (
 	"bytes"
 	"fmt"
+	"runtime"
 	"testing"
 
 	"github.com/jbrunton/gflows/io"
@@ -20,7 +21,7 @@ func newTestContext() *GFlowsContext {
 	}
 	fs := io.CreateMemFs()
 	out := new(bytes.Buffer)
-	logger := io.NewLogger(out, false, false)
+	logger := io.NewLogger(out, out, false, false)
 	context, err := NewContext(fs, logger, opts)
 	if err != nil {
 		panic(err)
@@ -36,6 +37,7 @@ func newTestCommand(run func(*cobra.Command, []string)) *cobra.Command {
 	cmd.Flags().String("config", "", "")
 	cmd.Flags().Bool("disable-colors", false, "")
 	cmd.Flags().Bool("debug", false, "")
+	cmd.Flags().IntP("jobs", "j", 0, "")
 	return cmd
 }
 
@@ -103,6 +105,18 @@ func TestCreateContextOpts(t *testing.T) {
 				EnableColors: true,
 			},
 		},
+		{
+			description: "specify jobs",
+			setup: func(cmd *cobra.Command) {
+				cmd.SetArgs([]string{"test", "-j", "4"})
+			},
+			expectedOpts: ContextOpts{
+				ConfigPath:   ".gflows/config.yml",
+				Engine:       "",
+				EnableColors: true,
+				Jobs:         4,
+			},
+		},
 	}
 
 	for _, scenario := range scenarios {
@@ -115,6 +129,25 @@ func TestCreateContextOpts(t *testing.T) {
 	}
 }
 
+func TestJobs(t *testing.T) {
+	fs := io.CreateMemFs()
+	logger, _ := io.NewTestLogger()
+	opts := ContextOpts{ConfigPath: ".gflows/config.yml", AllowNoContext: true}
+
+	context, err := NewContext(fs, logger, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, runtime.NumCPU(), context.Jobs)
+
+	opts.Jobs = 2
+	context, err = NewContext(fs, logger, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, context.Jobs)
+
+	opts.Jobs = -1
+	_, err = NewContext(fs, logger, opts)
+	assert.EqualError(t, err, "Invalid value for jobs: -1, must be at least 1")
+}
+
 func TestGetPathInfo(t *testing.T) {
 	context := newTestContext()
 
@@ -58,6 +58,22 @@ func TestUpdateCommand(t *testing.T) {
 	runTests(t, "./tests/update/ytt/*.yml", true)
 }
 
+func TestPruneCommand(t *testing.T) {
+	runTests(t, "./tests/prune/*.yml", true)
+}
+
+func TestRenderCommand(t *testing.T) {
+	runTests(t, "./tests/render/*.yml", true)
+}
+
+func TestDepsCommand(t *testing.T) {
+	runTests(t, "./tests/deps/*.yml", true)
+}
+
+func TestSchemaCommand(t *testing.T) {
+	runTests(t, "./tests/schema/*.yml", true)
+}
+
 func TestLocalLibs(t *testing.T) {
 	runTests(t, "./tests/local-libs/jsonnet/*.yml", false)
 	runTests(t, "./tests/local-libs/ytt/*.yml", false)
@@ -71,3 +87,7 @@ func TestGFlowsPkgs(t *testing.T) {
 	runTests(t, "./tests/gflowspkgs/jsonnet/*.yml", false)
 	runTests(t, "./tests/gflowspkgs/ytt/*", false)
 }
+
+func TestPkgCommand(t *testing.T) {
+	runTests(t, "./tests/pkg/*.yml", false)
+}
@@ -2,6 +2,7 @@ package runner
 
 import (
 	"bytes"
+	"encoding/json"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -33,8 +34,12 @@ type TestSetup struct {
 
 type TestExpect struct {
 	Output string
-	Error  string
-	Files  []TestFile
+	// Stderr - the expected warnings
+	Stderr string
+	// JSON - if true then the output must also parse as JSON
+	JSON  bool
+	Error string
+	Files []TestFile
 }
 
 type Test struct {
@@ -49,6 +54,7 @@ type TestRunner struct {
 	useMemFs     bool
 	fs           *afero.Afero
 	out          *bytes.Buffer
+	errOut       *bytes.Buffer
 	container    *content.Container
 	assert       Assertions
 	roundTripper *fixtures.TestRoundTripper
@@ -73,8 +79,9 @@ func NewTestRunner(osFs *afero.Afero, testPath string, useMemFs bool, assert Ass
 	}
 
 	out := new(bytes.Buffer)
+	errOut := new(bytes.Buffer)
 	roundTripper := fixtures.NewTestRoundTripper()
-	ioContainer := io.NewContainer(fs, io.NewLogger(out, false, false), styles.NewStyles(false), io.NewGoGitAdapter())
+	ioContainer := io.NewContainer(fs, io.NewLogger(out, errOut, false, false), styles.NewStyles(false), io.NewGoGitAdapter())
 	contentContainer := content.NewContainer(ioContainer, &http.Client{Transport: roundTripper})
 
 	return &TestRunner{
@@ -82,6 +89,7 @@ func NewTestRunner(osFs *afero.Afero, testPath string, useMemFs bool, assert Ass
 		test:         &test,
 		useMemFs:     useMemFs,
 		out:          out,
+		errOut:       errOut,
 		container:    contentContainer,
 		assert:       assert,
 		fs:           fs,
@@ -145,6 +153,11 @@ func (runner *TestRunner) Run() {
 		runner.assert.EqualError(err, runner.test.Expect.Error, "Unexpected error (%s)", runner.testPath)
 	}
 	runner.assert.Equal(runner.test.Expect.Output, runner.out.String(), "Unexpected output (%s)", runner.testPath)
+	runner.assert.Equal(runner.test.Expect.Stderr, runner.errOut.String(), "Unexpected stderr (%s)", runner.testPath)
+	if runner.test.Expect.JSON {
+		var output interface{}
+		runner.assert.NoError(json.Unmarshal(runner.out.Bytes(), &output), "Expected output to be JSON (%s)", runner.testPath)
+	}
 	if len(runner.test.Expect.Files) > 0 {
 		for _, expectedFile := range runner.test.Expect.Files {
 			exists, err := fs.Exists(expectedFile.Path)
@@ -180,9 +193,12 @@ func (runner *TestRunner) Run() {
 	}
 }
 
-func (runner *TestRunner) buildContainer(cmd *cobra.Command) (*action.Container, error) {
-	opts := config.CreateContextOpts(cmd)
+func (runner *TestRunner) buildContainer(command *cobra.Command) (*action.Container, error) {
+	opts := config.CreateContextOpts(command)
 	opts.EnableColors = false
+	opts.Version = cmd.Version
+	// the build cache lives outside the test directory, and is covered by unit tests
+	opts.NoCache = true
 	context, err := config.NewContext(runner.container.FileSystem(), runner.container.Logger(), opts)
 	if err != nil {
 		return nil, err
@@ -41,6 +41,11 @@ func TestRunnerUpToDate(t *testing.T) {
 		"Checking test ... OK\nWorkflows up to date\n",
 		"Checking test ... OK\nWorkflows up to date\n",
 		"Unexpected output (%s)", "./tests/test-runner-up-to-date.yml")
+	assertions.On(
+		"Equal",
+		"",
+		"",
+		"Unexpected stderr (%s)", "./tests/test-runner-up-to-date.yml")
 
 	runner.Run()
 
@@ -62,6 +67,11 @@ func TestRunnerOutOfDate(t *testing.T) {
 		"Checking test ... FAILED\n  Content is out of date for \"test\" (.github/workflows/test.yml)\n  ► Run \"gflows update\" to update\n",
 		"Checking test ... FAILED\n  Content is out of date for \"test\" (.github/workflows/test.yml)\n  ► Run \"gflows update\" to update\n",
 		"Unexpected output (%s)", "./tests/test-runner-out-of-date.yml")
+	assertions.On(
+		"Equal",
+		"",
+		"",
+		"Unexpected stderr (%s)", "./tests/test-runner-out-of-date.yml")
 
 	runner.Run()
 
@@ -1,6 +1,11 @@
 package env
 
 import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
 	"github.com/thoas/go-funk"
 
 	"github.com/jbrunton/gflows/config"
@@ -9,44 +14,321 @@ import (
 	"github.com/spf13/afero"
 )
 
+// LockMode - how the env treats dependencies which aren't in the lock file
+type LockMode int
+
+const (
+	// LockModeRecord - dependencies missing from the lock are added to it (when it's saved)
+	LockModeRecord LockMode = iota
+
+	// LockModeWarn - dependencies missing from the lock are recorded, with a warning that they
+	// aren't locked. Local dependencies are recorded without a warning, since they aren't fetched.
+	LockModeWarn
+)
+
+// GFlowsEnv - loads the packages used by the context (and the packages they depend on), and
+// verifies them against the lock file. Safe for concurrent use.
 type GFlowsEnv struct {
 	deps      map[string]*GFlowsLib
 	fs        *afero.Afero
 	installer *GFlowsLibInstaller
 	context   *config.GFlowsContext
 	logger    *io.Logger
+
+	// LockMode - how to treat dependencies missing from the lock file. Installed remote and git
+	// which don't match the lock are always errors.
+	LockMode LockMode
+
+	// lock - the lock file, loaded when the first dependency is installed
+	lock *GFlowsLock
+
+	// updates - the names (or paths) of packages whose lock entries should be replaced rather than
+	// verified. If it contains an empty string then all entries are replaced.
+	updates map[string]bool
+
+	// direct - the paths of dependencies loaded directly, rather than required by another package
+	direct map[string]bool
+
+	// packages - the installed packages keyed by name, to detect conflicting versions
+	packages map[string]*GFlowsLib
+
+	// requiredBy - describes what required each installed package (keyed by path), for errors
+	requiredBy map[string]string
+
+	// mutex - guards deps, direct, packages, requiredBy and lock. Held while installing, so that
+	// each dependency is only installed once.
+	mutex sync.Mutex
 }
 
 func NewGFlowsEnv(fs *afero.Afero, installer *GFlowsLibInstaller, context *config.GFlowsContext, logger *io.Logger) *GFlowsEnv {
 	return &GFlowsEnv{
-		deps:      make(map[string]*GFlowsLib),
-		fs:        fs,
-		installer: installer,
-		context:   context,
-		logger:    logger,
+		deps:       make(map[string]*GFlowsLib),
+		direct:     make(map[string]bool),
+		packages:   make(map[string]*GFlowsLib),
+		requiredBy: make(map[string]string),
+		fs:         fs,
+		installer:  installer,
+		context:    context,
+		logger:     logger,
 	}
 }
 
+// LoadDependency - installs the dependency (if it isn't already installed) and the packages it
+// depends on
 func (env *GFlowsEnv) LoadDependency(path string) (*GFlowsLib, error) {
+	env.mutex.Lock()
+	defer env.mutex.Unlock()
+	env.direct[path] = true
+	return env.loadDependency(path, []string{})
+}
+
+// loadDependency - installs the dependency and, recursively, its own dependencies. chain lists the
+// packages which led to this one, to detect cycles.
+func (env *GFlowsEnv) loadDependency(path string, chain []string) (*GFlowsLib, error) {
 	lib := env.deps[path]
 	if lib != nil {
 		// already processed
 		return lib, nil
 	}
+	if funk.ContainsString(chain, path) {
+		return nil, fmt.Errorf("Dependency cycle: %s", strings.Join(append(chain, path), " -> "))
+	}
 
 	lib, err := NewGFlowsLib(env.fs, env.installer, env.logger, path, env.context)
 	if err != nil {
 		return nil, err
 	}
-	err = lib.Setup()
+	lock, err := env.getLock()
+	if err != nil {
+		return nil, err
+	}
+	locked := lock.Packages[path]
+	if locked != nil && env.isUpdating(path, locked.Name) {
+		locked = nil
+	}
+	err = lib.Setup(locked)
+	if err != nil {
+		return nil, err
+	}
+	err = env.checkLock(path, lib)
+	if err != nil {
+		return nil, err
+	}
+	err = env.checkConflicts(lib, chain)
 	if err != nil {
 		return nil, err
 	}
 
+	depChain := append(append([]string{}, chain...), path)
+	for _, depPath := range lib.Dependencies {
+		_, err := env.loadDependency(depPath, depChain)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	env.deps[path] = lib
 	return lib, nil
 }
 
+// checkConflicts - returns an error if another version of the package (i.e. a package with the
+// same name from a different path) has already been installed
+func (env *GFlowsEnv) checkConflicts(lib *GFlowsLib, chain []string) error {
+	requiredBy := "config"
+	if len(chain) > 0 {
+		requiredBy = chain[len(chain)-1]
+	}
+
+	other := env.packages[lib.PackageName]
+	if other != nil && other.Path != lib.Path {
+		return fmt.Errorf("Conflicting versions of package %s: %s (required by %s) and %s (required by %s)",
+			lib.PackageName, other.Path, env.requiredBy[other.Path], lib.Path, requiredBy)
+	}
+	env.packages[lib.PackageName] = lib
+	if env.requiredBy[lib.Path] == "" {
+		env.requiredBy[lib.Path] = requiredBy
+	}
+	return nil
+}
+
+// GetTransitiveDependencies - loads the dependency, and returns it followed by the packages it
+// depends on (directly or indirectly), each listed once
+func (env *GFlowsEnv) GetTransitiveDependencies(path string) ([]*GFlowsLib, error) {
+	env.mutex.Lock()
+	defer env.mutex.Unlock()
+	env.direct[path] = true
+	lib, err := env.loadDependency(path, []string{})
+	if err != nil {
+		return nil, err
+	}
+	libs := []*GFlowsLib{lib}
+	seen := map[string]bool{path: true}
+	for i := 0; i < len(libs); i++ {
+		for _, depPath := range libs[i].Dependencies {
+			if !seen[depPath] {
+				seen[depPath] = true
+				libs = append(libs, env.deps[depPath])
+			}
+		}
+	}
+	return libs, nil
+}
+
+// LockPath - the path to the lock file
+func (env *GFlowsEnv) LockPath() string {
+	return filepath.Join(env.context.Dir, LockFileName)
+}
+
+func (env *GFlowsEnv) getLock() (*GFlowsLock, error) {
+	if env.lock != nil {
+		return env.lock, nil
+	}
+	exists, err := env.fs.Exists(env.LockPath())
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		env.lock = NewGFlowsLock()
+		return env.lock, nil
+	}
+	content, err := env.fs.ReadFile(env.LockPath())
+	if err != nil {
+		return nil, err
+	}
+	env.lock, err = ParseLock(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing %s: %s", env.LockPath(), err)
+	}
+	return env.lock, nil
+}
+
+// checkLock - verifies the installed dependency matches its lock entry, or records it if it isn't
+// locked yet (or is being updated). Local dependencies are always recorded rather than verified,
+// since they may be edited at any time.
+func (env *GFlowsEnv) checkLock(path string, lib *GFlowsLib) error {
+	lock, err := env.getLock()
+	if err != nil {
+		return err
+	}
+	installed, err := NewGFlowsLockEntry(env.fs, lib)
+	if err != nil {
+		return err
+	}
+
+	if env.isUpdating(path, lib.PackageName) || lib.isLocal() {
+		env.logger.Debugf("Locking %s at %s\n", lib.PackageName, path)
+		lock.Packages[path] = installed
+		return nil
+	}
+
+	entry := lock.Packages[path]
+	if entry == nil {
+		if env.LockMode == LockModeWarn {
+			env.logger.Warnfln("dependency %s is not in %s, run `gflows update` to add it", path, env.LockPath())
+		}
+		env.logger.Debugf("Adding %s to %s\n", path, env.LockPath())
+		lock.Packages[path] = installed
+		return nil
+	}
+
+	diffs := entry.Diff(installed)
+	if len(diffs) > 0 {
+		return fmt.Errorf("Dependency %s doesn't match %s (%s). If the change is expected, run `gflows pkg update %s`",
+			path, env.LockPath(), strings.Join(diffs, ", "), lib.PackageName)
+	}
+	env.logger.Debugf("Verified %s against %s\n", path, env.LockPath())
+	return nil
+}
+
+// isUpdating - returns true if the lock entry for the package should be replaced rather than
+// verified
+func (env *GFlowsEnv) isUpdating(path string, name string) bool {
+	return env.updates[""] || env.updates[path] || env.updates[name]
+}
+
+// SaveLock - writes the lock file if any dependencies were added to it or updated. Dependencies
+// which haven't been loaded are left as they are.
+func (env *GFlowsEnv) SaveLock() error {
+	env.mutex.Lock()
+	defer env.mutex.Unlock()
+	if env.lock == nil {
+		return nil
+	}
+	content := env.lock.Serialize()
+	if len(env.lock.Packages) == 0 {
+		exists, err := env.fs.Exists(env.LockPath())
+		if err != nil || !exists {
+			return err
+		}
+	}
+	existing, err := env.fs.ReadFile(env.LockPath())
+	if err == nil && string(existing) == content {
+		return nil
+	}
+	return env.installer.writer.UpdateFileContent(env.LockPath(), content, "")
+}
+
+// UpdateLock - reinstalls the given packages (identified by name or dependency path) and replaces
+// their entries in the lock file, or all dependencies if no names are given. Entries for
+// dependencies no longer required are removed.
+func (env *GFlowsEnv) UpdateLock(names []string) error {
+	env.mutex.Lock()
+	lock, err := env.getLock()
+	if err != nil {
+		env.mutex.Unlock()
+		return err
+	}
+	env.updates = make(map[string]bool)
+	if len(names) == 0 {
+		env.updates[""] = true
+	}
+	for _, name := range names {
+		env.updates[name] = true
+	}
+	env.mutex.Unlock()
+
+	dependencies := env.context.Config.GetAllDependencies()
+	for _, path := range dependencies {
+		_, err := env.LoadDependency(path)
+		if err != nil {
+			return err
+		}
+	}
+
+	env.mutex.Lock()
+	matched := make(map[string]bool)
+	for path, lib := range env.deps {
+		matched[path] = true
+		matched[lib.PackageName] = true
+	}
+	env.mutex.Unlock()
+
+	unknown := []string{}
+	for _, name := range names {
+		if !matched[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("Unknown package(s): %s", strings.Join(unknown, ", "))
+	}
+
+	env.mutex.Lock()
+	if len(names) == 0 {
+		for path := range lock.Packages {
+			if env.deps[path] == nil {
+				env.logger.Debugf("Removing %s from %s\n", path, env.LockPath())
+				delete(lock.Packages, path)
+			}
+		}
+	}
+	env.mutex.Unlock()
+
+	return env.SaveLock()
+}
+
+// GetPackages - returns the packages whose workflows should be generated, i.e. the dependencies
+// loaded directly (but not the packages they depend on) and the context itself
 func (env *GFlowsEnv) GetPackages() ([]pkg.GFlowsPackage, error) {
 	for _, libPath := range env.context.Config.GetAllDependencies() {
 		_, err := env.LoadDependency(libPath)
@@ -54,23 +336,32 @@ func (env *GFlowsEnv) GetPackages() ([]pkg.GFlowsPackage, error) {
 			return nil, err
 		}
 	}
-	deps := funk.Map(funk.Values(env.deps), func(dep *GFlowsLib) pkg.GFlowsPackage {
-		return dep
-	}).([]pkg.GFlowsPackage)
+	env.mutex.Lock()
+	defer env.mutex.Unlock()
+	deps := []pkg.GFlowsPackage{}
+	for path, dep := range env.deps {
+		if env.direct[path] {
+			deps = append(deps, dep)
+		}
+	}
 	return append(deps, env.context), nil
 }
 
 // GetLibPaths - returns search paths for the given workflow (including libs and local dependency
-// directories)
+// directories, and the libs of the packages the dependencies depend on)
 func (env *GFlowsEnv) GetLibPaths(workflowName string) ([]string, error) {
 	libPaths := env.context.Config.GetTemplateLibs(workflowName)
 	depPaths := env.context.Config.GetTemplateDeps(workflowName)
 	for _, depPath := range depPaths {
-		dep, err := env.LoadDependency(depPath)
+		deps, err := env.GetTransitiveDependencies(depPath)
 		if err != nil {
 			return nil, err
 		}
-		libPaths = append(libPaths, dep.LibsDir())
+		for _, dep := range deps {
+			if !funk.ContainsString(libPaths, dep.LibsDir()) {
+				libPaths = append(libPaths, dep.LibsDir())
+			}
+		}
 	}
 	contextLibPath := env.context.LibsDir()
 	libInfo, err := pkg.GetLibInfo(contextLibPath, env.fs)
@@ -84,9 +375,14 @@ func (env *GFlowsEnv) GetLibPaths(workflowName string) ([]string, error) {
 }
 
 func (env *GFlowsEnv) CleanUp() {
+	env.mutex.Lock()
+	defer env.mutex.Unlock()
 	env.installer.CleanUp()
 	for _, dep := range env.deps {
 		dep.CleanUp()
 	}
 	env.deps = make(map[string]*GFlowsLib)
+	env.direct = make(map[string]bool)
+	env.packages = make(map[string]*GFlowsLib)
+	env.requiredBy = make(map[string]string)
 }
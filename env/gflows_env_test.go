@@ -1,6 +1,7 @@
 package env
 
 import (
+	"bytes"
 	"net/http"
 	"path/filepath"
 	"strings"
@@ -13,13 +14,18 @@ import (
 )
 
 func newTestEnv(config string, roundTripper http.RoundTripper) (*GFlowsEnv, *content.Container) {
-	ioContainer, context, _ := fixtures.NewTestContext(config)
+	env, container, _ := newTestEnvWithOutput(config, roundTripper)
+	return env, container
+}
+
+func newTestEnvWithOutput(config string, roundTripper http.RoundTripper) (*GFlowsEnv, *content.Container, *bytes.Buffer) {
+	ioContainer, context, out := fixtures.NewTestContext(config)
 	httpClient := &http.Client{Transport: roundTripper}
 	container := content.NewContainer(ioContainer, httpClient)
 	repoManager := content.NewRepoManager(container.GitAdapter(), container.FileSystem(), container.Logger())
 	installer := NewGFlowsLibInstaller(container.FileSystem(), container.ContentReader(), container.ContentWriter(), container.Logger(), repoManager)
 	env := NewGFlowsEnv(container.FileSystem(), installer, context, container.Logger())
-	return env, container
+	return env, container, out
 }
 
 func TestLoadLocalLibrary(t *testing.T) {
@@ -48,7 +54,7 @@ func TestLoadRemoteLib(t *testing.T) {
 
 	assert.NoError(t, err)
 	assert.Equal(t, "my-lib", lib.PackageName)
-	fixtures.AssertTempDir(t, fs, "my-lib", lib.LocalDir)
+	assertPackageCached(t, fs, lib)
 	libContent, _ := fs.ReadFile(filepath.Join(lib.LocalDir, "libs/lib.yml"))
 	assert.Equal(t, "foo: bar", string(libContent))
 }
@@ -129,6 +135,29 @@ func TestGetLibPaths(t *testing.T) {
 	assert.Equal(t, []string{"/libs/some-lib", somePkg.LibsDir()}, paths)
 }
 
+func TestGetLibPathsTransitiveDependencies(t *testing.T) {
+	config := strings.Join([]string{
+		"templates:",
+		"  engine: jsonnet",
+		"  defaults:",
+		"    dependencies: [/deps/my-pkg, /deps/other-pkg]",
+	}, "\n")
+	env, container := newTestEnv(config, fixtures.NewMockRoundTripper())
+	container.ContentWriter().SafelyWriteFile("/deps/my-pkg/gflowspkg.json", `{"files": [], "dependencies": ["../shared-pkg"]}`)
+	container.ContentWriter().SafelyWriteFile("/deps/other-pkg/gflowspkg.json", `{"files": [], "dependencies": ["/deps/shared-pkg"]}`)
+	container.ContentWriter().SafelyWriteFile("/deps/shared-pkg/gflowspkg.json", `{"files": []}`)
+
+	paths, err := env.GetLibPaths("my-workflow")
+
+	assert.NoError(t, err)
+	myDeps, _ := env.GetTransitiveDependencies("/deps/my-pkg")
+	otherPkg, _ := env.LoadDependency("/deps/other-pkg")
+	assert.Len(t, myDeps, 2)
+	assert.Equal(t, []string{myDeps[0].LibsDir(), myDeps[1].LibsDir(), otherPkg.LibsDir()}, paths)
+	packages, _ := env.GetPackages()
+	assert.Len(t, packages, 3) // excludes shared-pkg, since it isn't a direct dependency
+}
+
 func TestGetLibPathsAddContextLibsDir(t *testing.T) {
 	config := strings.Join([]string{
 		"templates:",
@@ -174,3 +203,135 @@ func TestCleanUpEnv(t *testing.T) {
 	assert.NoError(t, err)
 	assert.False(t, exists, "expected LocalDir to have been removed")
 }
+
+func TestSaveLock(t *testing.T) {
+	env, container := newTestEnv("", fixtures.NewMockRoundTripper())
+	fs := container.FileSystem()
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/gflowspkg.json", `{"files": ["libs/lib.yml"]}`)
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/libs/lib.yml", "foo: bar")
+
+	_, err := env.LoadDependency("/path/to/my-lib")
+	assert.NoError(t, err)
+	assert.NoError(t, env.SaveLock())
+
+	content, _ := fs.ReadFile(".gflows/gflows.lock")
+	lock, err := ParseLock(string(content))
+	assert.NoError(t, err)
+	assert.Equal(t, &GFlowsLockEntry{
+		Name:   "my-lib",
+		Source: "/path/to/my-lib",
+	}, lock.Packages["/path/to/my-lib"])
+}
+
+func TestSaveLockRemoteLib(t *testing.T) {
+	roundTripper := fixtures.NewMockRoundTripper()
+	env, container := newTestEnv("", roundTripper)
+	fs := container.FileSystem()
+	roundTripper.StubBody("https://example.com/my-lib/gflowspkg.json", `{"files": ["libs/lib.yml"]}`)
+	roundTripper.StubBody("https://example.com/my-lib/libs/lib.yml", "foo: bar")
+
+	_, err := env.LoadDependency("https://example.com/my-lib")
+	assert.NoError(t, err)
+	assert.NoError(t, env.SaveLock())
+
+	content, _ := fs.ReadFile(".gflows/gflows.lock")
+	lock, err := ParseLock(string(content))
+	assert.NoError(t, err)
+	assert.Equal(t, &GFlowsLockEntry{
+		Name:   "my-lib",
+		Source: "https://example.com/my-lib",
+		Files:  map[string]string{"libs/lib.yml": "07091d9e7b63ac86966e39652ca5327568145ae7b61a16b7d5df29f918641ea5"},
+	}, lock.Packages["https://example.com/my-lib"])
+}
+
+func TestVerifyLock(t *testing.T) {
+	env, container := newTestEnv("", fixtures.NewMockRoundTripper())
+	env.context.NoCache = true
+	gitAdapter := container.GitAdapter().(*fixtures.TestGitAdapter)
+	gitAdapter.StubRepo("git@example.com:my/repo.git", &map[string]string{
+		"my-lib/gflowspkg.json": `{"files": ["libs/lib.yml"]}`,
+		"my-lib/libs/lib.yml":   "foo: bar",
+	})
+	_, err := env.LoadDependency("git@example.com:my/repo.git/my-lib")
+	assert.NoError(t, err)
+	assert.NoError(t, env.SaveLock())
+
+	gitAdapter.StubRepo("git@example.com:my/repo.git", &map[string]string{
+		"my-lib/gflowspkg.json": `{"files": ["libs/lib.yml"]}`,
+		"my-lib/libs/lib.yml":   "foo: baz",
+	})
+	env.CleanUp()
+	env.lock = nil
+	_, err = env.LoadDependency("git@example.com:my/repo.git/my-lib")
+
+	assert.EqualError(t, err, "Dependency git@example.com:my/repo.git/my-lib doesn't match .gflows/gflows.lock (libs/lib.yml changed). If the change is expected, run `gflows pkg update my-lib`")
+}
+
+func TestLocalLibsNotVerified(t *testing.T) {
+	env, container := newTestEnv("", fixtures.NewMockRoundTripper())
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/gflowspkg.json", `{"files": ["libs/lib.yml"]}`)
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/libs/lib.yml", "foo: bar")
+	_, err := env.LoadDependency("/path/to/my-lib")
+	assert.NoError(t, err)
+	assert.NoError(t, env.SaveLock())
+
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/gflowspkg.json", `{"files": ["libs/lib.yml"], "name": "renamed-lib"}`)
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/libs/lib.yml", "foo: baz")
+	env.CleanUp()
+	env.lock = nil
+	lib, err := env.LoadDependency("/path/to/my-lib")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "renamed-lib", lib.PackageName)
+}
+
+func TestWarnUnlocked(t *testing.T) {
+	roundTripper := fixtures.NewMockRoundTripper()
+	env, container, out := newTestEnvWithOutput("", roundTripper)
+	roundTripper.StubBody("https://example.com/my-lib/gflowspkg.json", `{"files": []}`)
+	container.ContentWriter().SafelyWriteFile("/path/to/local-lib/gflowspkg.json", `{"files": []}`)
+	env.LockMode = LockModeWarn
+
+	_, err := env.LoadDependency("https://example.com/my-lib")
+	assert.NoError(t, err)
+	_, err = env.LoadDependency("/path/to/local-lib")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Warning: dependency https://example.com/my-lib is not in .gflows/gflows.lock, run `gflows update` to add it\n", out.String())
+}
+
+func TestUpdateLock(t *testing.T) {
+	config := strings.Join([]string{
+		"templates:",
+		"  engine: ytt",
+		"  defaults:",
+		"    dependencies: [git@example.com:my/repo.git/my-lib@v1]",
+	}, "\n")
+	env, container := newTestEnv(config, fixtures.NewMockRoundTripper())
+	fs := container.FileSystem()
+	gitAdapter := container.GitAdapter().(*fixtures.TestGitAdapter)
+	gitAdapter.StubRepo("git@example.com:my/repo.git", &map[string]string{})
+	gitAdapter.StubRef("git@example.com:my/repo.git", "v1", &map[string]string{
+		"my-lib/gflowspkg.json": `{"files": []}`,
+	})
+	container.ContentWriter().SafelyWriteFile(".gflows/gflows.lock", `{"packages": {
+		"git@example.com:my/repo.git/my-lib@v1": {"name": "my-lib", "source": "git@example.com:my/repo.git/my-lib@v1", "commit": "abc123"},
+		"/path/to/old-lib": {"name": "old-lib", "source": "/path/to/old-lib"}
+	}}`)
+
+	err := env.UpdateLock([]string{"my-lib"})
+	assert.NoError(t, err)
+	content, _ := fs.ReadFile(".gflows/gflows.lock")
+	lock, _ := ParseLock(string(content))
+	assert.Equal(t, fixtures.TestCommit("git@example.com:my/repo.git", "v1"), lock.Packages["git@example.com:my/repo.git/my-lib@v1"].Commit)
+	assert.Contains(t, lock.Packages, "/path/to/old-lib")
+
+	err = env.UpdateLock([]string{})
+	assert.NoError(t, err)
+	content, _ = fs.ReadFile(".gflows/gflows.lock")
+	lock, _ = ParseLock(string(content))
+	assert.NotContains(t, lock.Packages, "/path/to/old-lib")
+
+	err = env.UpdateLock([]string{"other-lib"})
+	assert.EqualError(t, err, "Unknown package(s): other-lib")
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/jbrunton/gflows/io/pkg"
@@ -33,6 +34,15 @@ type GFlowsLib struct {
 	// Files - content of the package as an array of FileInfo
 	Files []*pkg.PathInfo
 
+	// Commit - for git packages, the SHA of the commit installed
+	Commit string
+
+	// Dependencies - the resolved paths of the packages given in the manifest's dependencies
+	Dependencies []string
+
+	// cached - true if LocalDir is in the package cache, in which case it isn't removed by CleanUp
+	cached bool
+
 	fs        *afero.Afero
 	installer *GFlowsLibInstaller
 	context   *config.GFlowsContext
@@ -48,7 +58,7 @@ func NewGFlowsLib(fs *afero.Afero, installer *GFlowsLibInstaller, logger *io.Log
 	return &GFlowsLib{
 		Path:         resolvedPath,
 		ManifestPath: manifestPath,
-		PackageName:  filepath.Base(resolvedPath),
+		PackageName:  defaultPackageName(resolvedPath),
 		installer:    installer,
 		fs:           fs,
 		context:      context,
@@ -56,7 +66,20 @@ func NewGFlowsLib(fs *afero.Afero, installer *GFlowsLibInstaller, logger *io.Log
 	}, nil
 }
 
+// defaultPackageName - the name of the package if the manifest doesn't give one, i.e. the name of
+// the directory containing it (excluding any git ref)
+func defaultPackageName(path string) string {
+	if pkg.IsGitPath(path) {
+		repo, subdir, _ := pkg.ParseGitPath(path)
+		path = repo + subdir
+	}
+	return filepath.Base(path)
+}
+
 func (lib *GFlowsLib) CleanUp() {
+	if lib.cached {
+		return
+	}
 	lib.logger.Debug("Removing temp directory", lib.LocalDir)
 	lib.fs.RemoveAll(lib.LocalDir)
 }
@@ -91,12 +114,27 @@ func (lib *GFlowsLib) GetPathInfo(localPath string) (*pkg.PathInfo, error) {
 		// TODO: Description should be SourcePath if remote, relative SourcePath if within context dir (i.e. in source control),
 		// and in terms of library name otherwise (since in that case the path is local but outside the repo, so not v useful)
 		Description: path.Join(lib.PackageName, relPath),
+		PackageName: lib.PackageName,
+		Commit:      lib.Commit,
 	}, err
 }
 
-func (lib *GFlowsLib) Setup() error {
+// Setup - installs the package. Remote and git packages are installed into the package cache, and
+// if locked is given (and matches the package) then a cached copy is used if there is one.
+func (lib *GFlowsLib) Setup(locked *GFlowsLockEntry) error {
 	lib.logger.Debugf("Installing %s (%s)\n", lib.PackageName, lib.Path)
 
+	cacheable := lib.isCacheable()
+	if cacheable && locked != nil {
+		restored, err := lib.restore(locked)
+		if err != nil || restored {
+			return err
+		}
+	}
+	if lib.context.Offline && pkg.IsRemotePath(lib.Path) {
+		return fmt.Errorf("%s isn't cached, and can't be downloaded with --offline", lib.Path)
+	}
+
 	tempDir, err := lib.fs.TempDir("", lib.PackageName)
 	if err != nil {
 		return err
@@ -106,14 +144,145 @@ func (lib *GFlowsLib) Setup() error {
 	files, manifest, err := lib.installer.install(lib)
 	lib.Files = files
 
+	if err == nil && cacheable {
+		err = lib.store()
+	}
+
 	if err == nil {
-		lib.logger.Debugf("Installed %s\n", lib.PackageName)
+		if lib.Commit != "" {
+			lib.logger.Debugf("Installed %s at %s\n", lib.PackageName, lib.Commit)
+		} else {
+			lib.logger.Debugf("Installed %s\n", lib.PackageName)
+		}
 		lib.logger.Debugf("Installed %s\n", spew.Sdump(lib.Files))
 
 		if manifest.Name != "" {
 			lib.PackageName = manifest.Name
 		}
+		lib.Dependencies, err = lib.resolveDependencies(manifest.Dependencies)
 	}
 
 	return err
 }
+
+// resolveDependencies - resolves the dependency paths given in the manifest. Relative paths are
+// resolved against the package path, and for git packages refer to the same repository and ref.
+func (lib *GFlowsLib) resolveDependencies(paths []string) ([]string, error) {
+	dependencies := []string{}
+	for _, depPath := range paths {
+		if pkg.IsRemotePath(depPath) || pkg.IsGitPath(depPath) || filepath.IsAbs(depPath) {
+			dependencies = append(dependencies, depPath)
+			continue
+		}
+		if !pkg.IsGitPath(lib.Path) {
+			resolvedPath, err := pkg.JoinRelativePath(lib.Path, depPath)
+			if err != nil {
+				return nil, err
+			}
+			dependencies = append(dependencies, resolvedPath)
+			continue
+		}
+		repo, subdir, ref := pkg.ParseGitPath(lib.Path)
+		relPath := path.Join(strings.TrimPrefix(subdir, "/"), depPath)
+		if relPath == ".." || strings.HasPrefix(relPath, "../") {
+			return nil, fmt.Errorf("Dependency %s of %s is outside the repository %s", depPath, lib.Path, repo)
+		}
+		resolvedPath := repo
+		if relPath != "." {
+			resolvedPath = resolvedPath + "/" + relPath
+		}
+		if ref != "" {
+			resolvedPath = resolvedPath + "@" + ref
+		}
+		dependencies = append(dependencies, resolvedPath)
+	}
+	return dependencies, nil
+}
+
+// isCacheable - returns true if the package should be installed into the package cache. Local
+// packages aren't cached, since they may change at any time.
+func (lib *GFlowsLib) isCacheable() bool {
+	return !lib.context.NoCache && !lib.isLocal()
+}
+
+// isLocal - returns true if the package is read from a local directory, rather than fetched
+func (lib *GFlowsLib) isLocal() bool {
+	return !pkg.IsRemotePath(lib.Path) && !pkg.IsGitPath(lib.Path)
+}
+
+// restore - uses the cached copy of the locked version of the package, if there is one
+func (lib *GFlowsLib) restore(locked *GFlowsLockEntry) (bool, error) {
+	if locked.Source != lib.Path {
+		return false, nil
+	}
+	version := locked.Commit
+	if !pkg.IsGitPath(lib.Path) {
+		version = ContentVersion(locked.Files)
+	}
+	exists, err := lib.installer.cache.Exists(lib.Path, version)
+	if err != nil || !exists {
+		return false, err
+	}
+	dir := lib.installer.cache.Path(lib.Path, version)
+
+	lib.logger.Debugf("Using cached copy of %s (%s)\n", lib.Path, dir)
+	lib.LocalDir = dir
+	lib.Commit = locked.Commit
+	lib.Dependencies = locked.Dependencies
+	lib.cached = true
+	relPaths := []string{}
+	for relPath := range locked.Files {
+		relPaths = append(relPaths, relPath)
+	}
+	sort.Strings(relPaths)
+	err = lib.setFiles(relPaths)
+	if err != nil {
+		return false, err
+	}
+	lib.PackageName = locked.Name
+	return true, nil
+}
+
+// store - copies the installed package into the package cache, and removes the temp dir
+func (lib *GFlowsLib) store() error {
+	entry, err := NewGFlowsLockEntry(lib.fs, lib)
+	if err != nil {
+		return err
+	}
+	version := lib.Commit
+	if !pkg.IsGitPath(lib.Path) {
+		version = ContentVersion(entry.Files)
+	}
+	relPaths := []string{}
+	for _, file := range lib.Files {
+		relPath, err := filepath.Rel(lib.LocalDir, file.LocalPath)
+		if err != nil {
+			return err
+		}
+		relPaths = append(relPaths, filepath.ToSlash(relPath))
+	}
+
+	cacheDir, err := lib.installer.cache.Store(lib.LocalDir, lib.Path, version)
+	if err != nil {
+		return err
+	}
+	lib.logger.Debug("Removing temp directory", lib.LocalDir)
+	lib.fs.RemoveAll(lib.LocalDir)
+	lib.LocalDir = cacheDir
+	lib.cached = true
+	return lib.setFiles(relPaths)
+}
+
+// setFiles - sets Files to the given paths, relative to LocalDir
+func (lib *GFlowsLib) setFiles(relPaths []string) error {
+	files := []*pkg.PathInfo{}
+	for _, relPath := range relPaths {
+		pathInfo, err := lib.GetPathInfo(filepath.Join(lib.LocalDir, filepath.FromSlash(relPath)))
+		if err != nil {
+			return err
+		}
+		files = append(files, pathInfo)
+	}
+	lib.Files = files
+	return nil
+}
@@ -3,8 +3,11 @@ package env
 import (
 	"fmt"
 	"path"
+	"path/filepath"
 	"strings"
 
+	"github.com/thoas/go-funk"
+
 	"github.com/jbrunton/gflows/io"
 	"github.com/jbrunton/gflows/io/content"
 	"github.com/jbrunton/gflows/io/pkg"
@@ -17,6 +20,7 @@ type GFlowsLibInstaller struct {
 	writer      *content.Writer
 	logger      *io.Logger
 	repoManager *content.RepoManager
+	cache       *PackageCache
 }
 
 func NewGFlowsLibInstaller(
@@ -32,16 +36,22 @@ func NewGFlowsLibInstaller(
 		writer:      writer,
 		logger:      logger,
 		repoManager: repoManager,
+		cache:       NewPackageCache(fs),
 	}
 }
 
 func (installer *GFlowsLibInstaller) install(lib *GFlowsLib) ([]*pkg.PathInfo, *GFlowsLibManifest, error) {
 	if pkg.IsGitPath(lib.Path) {
-		repoUrl, subdir := pkg.ParseGitPath(lib.Path)
-		repo, err := installer.repoManager.GetRepo(repoUrl)
+		repoUrl, subdir, ref := pkg.ParseGitPath(lib.Path)
+		repo, err := installer.repoManager.GetRepo(repoUrl, content.RepoOptions{
+			Ref:     ref,
+			NoCache: lib.context.NoCache,
+			Offline: lib.context.Offline,
+		})
 		if err != nil {
 			return nil, nil, err
 		}
+		lib.Commit = repo.Commit
 
 		lib.ManifestPath, err = pkg.JoinRelativePath(repo.LocalDir, path.Join(subdir, "gflowspkg.json"))
 		if err != nil {
@@ -59,8 +69,13 @@ func (installer *GFlowsLibInstaller) install(lib *GFlowsLib) ([]*pkg.PathInfo, *
 		return nil, nil, err
 	}
 
+	relPaths, err := installer.expandFiles(rootPath, manifest.Files)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	files := []*pkg.PathInfo{}
-	for _, relPath := range manifest.Files {
+	for _, relPath := range relPaths {
 		localPath, err := installer.copyFile(lib, rootPath, relPath)
 		if err != nil {
 			return nil, nil, err
@@ -91,6 +106,62 @@ func (installer *GFlowsLibInstaller) loadManifest(manifestPath string) (*GFlowsL
 	return manifest, err
 }
 
+// expandFiles - expands any globs or directories given in the manifest files into the paths of the
+// files they match, for local and git packages. Remote packages must list each file, since they
+// can't be globbed or listed, so entries which look like directories (i.e. with a trailing slash or
+// no extension) are rejected.
+func (installer *GFlowsLibInstaller) expandFiles(rootPath string, patterns []string) ([]string, error) {
+	if pkg.IsRemotePath(rootPath) {
+		for _, pattern := range patterns {
+			if pkg.IsGlob(pattern) || strings.HasSuffix(pattern, "/") || path.Ext(pattern) == "" {
+				return nil, fmt.Errorf("Unable to expand %s in %s, remote packages must list their files (see `gflows pkg manifest`)", pattern, rootPath)
+			}
+		}
+		return patterns, nil
+	}
+
+	sourceFiles, err := ListPackageFiles(installer.fs, rootPath)
+	if err != nil {
+		return nil, err
+	}
+	relPaths := []string{}
+	for _, pattern := range patterns {
+		matches := []string{}
+		if pkg.IsGlob(pattern) {
+			for _, sourceFile := range sourceFiles {
+				matched, err := pkg.MatchGlob(pattern, sourceFile)
+				if err != nil {
+					return nil, fmt.Errorf("Invalid pattern %s in %s: %s", pattern, rootPath, err)
+				}
+				if matched {
+					matches = append(matches, sourceFile)
+				}
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("No files in %s match %s", rootPath, pattern)
+			}
+		} else if isDir, _ := installer.fs.IsDir(filepath.Join(rootPath, pattern)); isDir {
+			dirPrefix := strings.TrimSuffix(pattern, "/") + "/"
+			for _, sourceFile := range sourceFiles {
+				if strings.HasPrefix(sourceFile, dirPrefix) {
+					matches = append(matches, sourceFile)
+				}
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("No files in %s match %s", rootPath, pattern)
+			}
+		} else {
+			matches = append(matches, pattern)
+		}
+		for _, match := range matches {
+			if !funk.ContainsString(relPaths, match) {
+				relPaths = append(relPaths, match)
+			}
+		}
+	}
+	return relPaths, nil
+}
+
 func (installer *GFlowsLibInstaller) copyFile(lib *GFlowsLib, rootPath string, relPath string) (string, error) {
 	if !strings.HasPrefix(relPath, "libs/") && !strings.HasPrefix(relPath, "workflows/") {
 		return "", fmt.Errorf("Unexpected directory %s, file must be in libs/ or workflows/", relPath)
@@ -116,6 +187,34 @@ func (installer *GFlowsLibInstaller) copyFile(lib *GFlowsLib, rootPath string, r
 	return localPath, err
 }
 
+// UpdateManifest - sets the files in the manifest of the package in dir to the files in its libs/
+// and workflows/ directories (creating the manifest if there isn't one), so that the package can be
+// hosted remotely
+func (installer *GFlowsLibInstaller) UpdateManifest(dir string) error {
+	manifestPath := filepath.Join(dir, "gflowspkg.json")
+	exists, err := installer.fs.Exists(manifestPath)
+	if err != nil {
+		return err
+	}
+	manifest := &GFlowsLibManifest{}
+	if exists {
+		manifest, err = installer.loadManifest(manifestPath)
+		if err != nil {
+			return fmt.Errorf("Error parsing %s: %s", manifestPath, err)
+		}
+	}
+
+	manifest.Files, err = ListPackageFiles(installer.fs, dir)
+	if err != nil {
+		return err
+	}
+	if manifest.Libs != nil {
+		installer.logger.Printfln(`Merged deprecated "libs" field into "files" in %s`, manifestPath)
+		manifest.Libs = nil
+	}
+	return installer.writer.UpdateFileContent(manifestPath, manifest.Serialize(), "")
+}
+
 func (installer *GFlowsLibInstaller) CleanUp() {
 	installer.repoManager.CleanUp()
 }
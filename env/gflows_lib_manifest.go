@@ -2,18 +2,29 @@ package env
 
 import (
 	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
 )
 
 type GFlowsLibManifest struct {
 	// Files - the list of files in the library. If the manifest is remote, this list is used to
-	// download the files.
-	Files []string
+	// download the files. Local and git packages may also give directories and globs (e.g.
+	// libs/**/*.libsonnet), which match files in libs/ and workflows/.
+	Files []string `json:"files"`
 
 	// Libs - deprecated field, use Files instead
-	Libs []string
+	Libs []string `json:"libs,omitempty"`
 
 	// Name - the name of the package
-	Name string
+	Name string `json:"name,omitempty"`
+
+	// Dependencies - other packages the package depends on, whose libs are added to the lib paths
+	// of any workflow which uses the package. Relative paths are resolved against the package
+	// path, so a git package may depend on another package in the same repository and ref.
+	Dependencies []string `json:"dependencies,omitempty"`
 }
 
 func ParseManifest(content string) (*GFlowsLibManifest, error) {
@@ -21,3 +32,52 @@ func ParseManifest(content string) (*GFlowsLibManifest, error) {
 	err := json.Unmarshal([]byte(content), &manifest)
 	return &manifest, err
 }
+
+// Serialize - returns the content of the manifest file
+func (manifest *GFlowsLibManifest) Serialize() string {
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	return string(content) + "\n"
+}
+
+// ListPackageFiles - returns the paths of the files in the libs/ and workflows/ directories of the
+// package in rootPath, relative to rootPath. Hidden files and directories (e.g. .DS_Store) are
+// skipped.
+func ListPackageFiles(fs *afero.Afero, rootPath string) ([]string, error) {
+	relPaths := []string{}
+	for _, dir := range []string{"libs", "workflows"} {
+		exists, err := fs.DirExists(filepath.Join(rootPath, dir))
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			continue
+		}
+		err = fs.Walk(filepath.Join(rootPath, dir), func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if strings.HasPrefix(info.Name(), ".") {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if info.IsDir() {
+				return nil
+			}
+			relPath, err := filepath.Rel(rootPath, path)
+			if err != nil {
+				return err
+			}
+			relPaths = append(relPaths, filepath.ToSlash(relPath))
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return relPaths, nil
+}
@@ -4,11 +4,14 @@ import (
 	"fmt"
 	"net/http"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/jbrunton/gflows/fixtures"
+	"github.com/jbrunton/gflows/io"
 	"github.com/jbrunton/gflows/io/content"
 	"github.com/jbrunton/gflows/io/pkg"
 )
@@ -30,7 +33,7 @@ func TestSetupLocalLib(t *testing.T) {
 	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/gflowspkg.json", `{"files": ["libs/lib.yml"]}`)
 	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/libs/lib.yml", "foo: bar")
 
-	err := lib.Setup()
+	err := lib.Setup(nil)
 
 	assert.NoError(t, err)
 	fixtures.AssertTempDir(t, fs, "my-lib", lib.LocalDir)
@@ -44,10 +47,10 @@ func TestSetupRemoteLib(t *testing.T) {
 	roundTripper.StubBody("https://example.com/path/to/my-lib/gflowspkg.json", `{"files": ["libs/lib.yml"]}`)
 	roundTripper.StubBody("https://example.com/path/to/my-lib/libs/lib.yml", "foo: bar")
 
-	err := lib.Setup()
+	err := lib.Setup(nil)
 
 	assert.NoError(t, err)
-	fixtures.AssertTempDir(t, fs, "my-lib", lib.LocalDir)
+	assertPackageCached(t, fs, lib)
 	libContent, _ := fs.ReadFile(filepath.Join(lib.LocalDir, "libs/lib.yml"))
 	assert.Equal(t, "foo: bar", string(libContent))
 }
@@ -61,10 +64,10 @@ func TestSetupGitLib(t *testing.T) {
 		"libs/lib.yml":   "foo: bar",
 	})
 
-	err := lib.Setup()
+	err := lib.Setup(nil)
 
 	assert.NoError(t, err)
-	fixtures.AssertTempDir(t, fs, "repo.git", lib.LocalDir)
+	assertPackageCached(t, fs, lib)
 	libContent, _ := fs.ReadFile(filepath.Join(lib.LocalDir, "libs/lib.yml"))
 	assert.Equal(t, "foo: bar", string(libContent))
 }
@@ -78,12 +81,208 @@ func TestSetupNestedGitLib(t *testing.T) {
 		"my-lib/libs/lib.yml":   "foo: bar",
 	})
 
-	err := lib.Setup()
+	err := lib.Setup(nil)
 
 	assert.NoError(t, err)
-	fixtures.AssertTempDir(t, fs, "my-lib", lib.LocalDir)
+	assertPackageCached(t, fs, lib)
+	libContent, _ := fs.ReadFile(filepath.Join(lib.LocalDir, "libs/lib.yml"))
+	assert.Equal(t, "foo: bar", string(libContent))
+}
+
+func TestSetupPinnedGitLib(t *testing.T) {
+	lib, container, _ := newTestLib("git@example.com:my/repo.git/my-lib@v1.2.0")
+	fs := container.FileSystem()
+	gitAdapter := container.GitAdapter().(*fixtures.TestGitAdapter)
+	gitAdapter.StubRepo("git@example.com:my/repo.git", &map[string]string{
+		"my-lib/gflowspkg.json": `{"files": ["libs/lib.yml"]}`,
+		"my-lib/libs/lib.yml":   "foo: baz",
+	})
+	gitAdapter.StubRef("git@example.com:my/repo.git", "v1.2.0", &map[string]string{
+		"my-lib/gflowspkg.json": `{"files": ["libs/lib.yml"]}`,
+		"my-lib/libs/lib.yml":   "foo: bar",
+	})
+
+	err := lib.Setup(nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "my-lib", lib.PackageName)
+	assertPackageCached(t, fs, lib)
 	libContent, _ := fs.ReadFile(filepath.Join(lib.LocalDir, "libs/lib.yml"))
 	assert.Equal(t, "foo: bar", string(libContent))
+	expectedCommit := fixtures.TestCommit("git@example.com:my/repo.git", "v1.2.0")
+	assert.Equal(t, expectedCommit, lib.Commit)
+	assert.Equal(t, expectedCommit, lib.Files[0].Commit)
+}
+
+func TestRestoreLockedLib(t *testing.T) {
+	lib, container, roundTripper := newTestLib("https://example.com/path/to/my-lib")
+	fs := container.FileSystem()
+	roundTripper.StubBody("https://example.com/path/to/my-lib/gflowspkg.json", `{"files": ["libs/lib.yml"]}`)
+	roundTripper.StubBody("https://example.com/path/to/my-lib/libs/lib.yml", "foo: bar")
+	err := lib.Setup(nil)
+	assert.NoError(t, err)
+	locked, _ := NewGFlowsLockEntry(fs, lib)
+
+	restored, _ := NewGFlowsLib(fs, lib.installer, lib.logger, "https://example.com/path/to/my-lib", lib.context)
+	err = restored.Setup(locked)
+
+	assert.NoError(t, err)
+	assert.Equal(t, lib.LocalDir, restored.LocalDir)
+	assert.Equal(t, filepath.Join(lib.LocalDir, "libs/lib.yml"), restored.Files[0].LocalPath)
+	roundTripper.AssertNumberOfCalls(t, "RoundTrip", 2) // only called by the first install
+}
+
+func TestRestoreLockedLibOffline(t *testing.T) {
+	lib, _, roundTripper := newTestLib("https://example.com/path/to/my-lib")
+	lib.context.Offline = true
+	locked := &GFlowsLockEntry{Name: "my-lib", Source: "https://example.com/path/to/my-lib", Files: map[string]string{"libs/lib.yml": "abc123"}}
+
+	err := lib.Setup(locked)
+
+	assert.EqualError(t, err, "https://example.com/path/to/my-lib isn't cached, and can't be downloaded with --offline")
+	roundTripper.AssertNumberOfCalls(t, "RoundTrip", 0)
+}
+
+func TestSetupLocalLibOffline(t *testing.T) {
+	lib, container, _ := newTestLib("/path/to/my-lib")
+	lib.context.Offline = true
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/gflowspkg.json", `{"files": []}`)
+
+	err := lib.Setup(nil)
+
+	assert.NoError(t, err)
+}
+
+func TestSetupLibDependencies(t *testing.T) {
+	lib, container, _ := newTestLib("git@example.com:my/repo.git/libs/my-lib@v1")
+	gitAdapter := container.GitAdapter().(*fixtures.TestGitAdapter)
+	gitAdapter.StubRepo("git@example.com:my/repo.git", &map[string]string{})
+	gitAdapter.StubRef("git@example.com:my/repo.git", "v1", &map[string]string{
+		"libs/my-lib/gflowspkg.json": `{"files": [], "dependencies": ["../other-lib", "https://example.com/remote-lib", "/path/to/local-lib"]}`,
+	})
+
+	err := lib.Setup(nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"git@example.com:my/repo.git/libs/other-lib@v1",
+		"https://example.com/remote-lib",
+		"/path/to/local-lib",
+	}, lib.Dependencies)
+}
+
+func TestSetupLibDependencyOutsideRepo(t *testing.T) {
+	lib, container, _ := newTestLib("git@example.com:my/repo.git/my-lib")
+	gitAdapter := container.GitAdapter().(*fixtures.TestGitAdapter)
+	gitAdapter.StubRepo("git@example.com:my/repo.git", &map[string]string{
+		"my-lib/gflowspkg.json": `{"files": [], "dependencies": ["../../other-lib"]}`,
+	})
+
+	err := lib.Setup(nil)
+
+	assert.EqualError(t, err, "Dependency ../../other-lib of git@example.com:my/repo.git/my-lib is outside the repository git@example.com:my/repo.git")
+}
+
+func TestSetupLibWithGlobs(t *testing.T) {
+	lib, container, _ := newTestLib("/path/to/my-lib")
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/gflowspkg.json", `{"files": ["libs/**/*.yml", "workflows/test", "libs/a.yml"]}`)
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/libs/a.yml", "a: a")
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/libs/nested/b.yml", "b: b")
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/libs/c.txt", "c")
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/workflows/test/config.yml", "foo: bar")
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/workflows/test/.DS_Store", "")
+
+	err := lib.Setup(nil)
+
+	assert.NoError(t, err)
+	descriptions := []string{}
+	for _, file := range lib.Files {
+		descriptions = append(descriptions, file.Description)
+	}
+	assert.Equal(t, []string{"my-lib/libs/a.yml", "my-lib/libs/nested/b.yml", "my-lib/workflows/test/config.yml"}, descriptions)
+}
+
+func TestSetupGitLibWithGlobs(t *testing.T) {
+	lib, container, _ := newTestLib("git@example.com:my/repo.git/my-lib")
+	gitAdapter := container.GitAdapter().(*fixtures.TestGitAdapter)
+	gitAdapter.StubRepo("git@example.com:my/repo.git", &map[string]string{
+		"my-lib/gflowspkg.json":    `{"files": ["libs/*.yml"]}`,
+		"my-lib/libs/lib.yml":      "foo: bar",
+		"other-lib/libs/other.yml": "foo: baz",
+	})
+
+	err := lib.Setup(nil)
+
+	assert.NoError(t, err)
+	assert.Len(t, lib.Files, 1)
+	assert.Equal(t, "my-lib/libs/lib.yml", lib.Files[0].Description)
+}
+
+func TestSetupLibGlobWithoutMatches(t *testing.T) {
+	lib, container, _ := newTestLib("/path/to/my-lib")
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/gflowspkg.json", `{"files": ["libs/*.libsonnet"]}`)
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/libs/lib.yml", "foo: bar")
+
+	err := lib.Setup(nil)
+
+	assert.EqualError(t, err, "No files in /path/to/my-lib match libs/*.libsonnet")
+}
+
+func TestSetupLibDirectoryWithoutFiles(t *testing.T) {
+	lib, container, _ := newTestLib("/path/to/my-lib")
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/gflowspkg.json", `{"files": ["libs/empty"]}`)
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/libs/empty/.DS_Store", "")
+
+	err := lib.Setup(nil)
+
+	assert.EqualError(t, err, "No files in /path/to/my-lib match libs/empty")
+}
+
+func TestSetupRemoteLibWithDirectory(t *testing.T) {
+	lib, _, roundTripper := newTestLib("https://example.com/path/to/my-lib")
+	roundTripper.StubBody("https://example.com/path/to/my-lib/gflowspkg.json", `{"files": ["libs/"]}`)
+
+	err := lib.Setup(nil)
+
+	assert.EqualError(t, err, "Unable to expand libs/ in https://example.com/path/to/my-lib, remote packages must list their files (see `gflows pkg manifest`)")
+}
+
+func TestUpdateManifest(t *testing.T) {
+	lib, container, _ := newTestLib("/path/to/my-lib")
+	fs := container.FileSystem()
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/gflowspkg.json", `{"name": "my-lib", "libs": ["libs/a.yml"], "dependencies": ["../other-lib"]}`)
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/libs/a.yml", "a: a")
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/libs/nested/b.yml", "b: b")
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/libs/.DS_Store", "")
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/workflows/test.jsonnet", "{}")
+
+	err := lib.installer.UpdateManifest("/path/to/my-lib")
+
+	assert.NoError(t, err)
+	manifestContent, _ := fs.ReadFile("/path/to/my-lib/gflowspkg.json")
+	assert.Equal(t, strings.Join([]string{
+		`{`,
+		`  "files": [`,
+		`    "libs/a.yml",`,
+		`    "libs/nested/b.yml",`,
+		`    "workflows/test.jsonnet"`,
+		`  ],`,
+		`  "name": "my-lib",`,
+		`  "dependencies": [`,
+		`    "../other-lib"`,
+		`  ]`,
+		`}`,
+	}, "\n")+"\n", string(manifestContent))
+}
+
+func assertPackageCached(t *testing.T, fs *afero.Afero, lib *GFlowsLib) {
+	assert.True(t, strings.HasPrefix(lib.LocalDir, io.CacheDir("packages")), "expected %s to be in the package cache", lib.LocalDir)
+	exists, _ := fs.Exists(filepath.Join(lib.LocalDir, ".gflows-installed"))
+	assert.True(t, exists, "expected %s to be marked as installed", lib.LocalDir)
+	entries, _ := fs.ReadDir(io.CacheDir("packages"))
+	for _, entry := range entries {
+		assert.False(t, strings.HasPrefix(entry.Name(), ".installing-"), "expected temp dirs to be removed")
+	}
 }
 
 func TestLibStructureErrors(t *testing.T) {
@@ -91,7 +290,7 @@ func TestLibStructureErrors(t *testing.T) {
 	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/gflowspkg.json", `{"files": ["foo/lib.yml"]}`)
 	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/foo/lib.yml", "foo: bar")
 
-	err := lib.Setup()
+	err := lib.Setup(nil)
 
 	assert.EqualError(t, err, "Unexpected directory foo/lib.yml, file must be in libs/ or workflows/")
 }
@@ -103,7 +302,7 @@ func TestCleanUpLib(t *testing.T) {
 	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/gflowspkg.json", `{"files": ["libs/lib.yml"]}`)
 	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/libs/lib.yml", "foo: bar")
 
-	err := lib.Setup()
+	err := lib.Setup(nil)
 	assert.NoError(t, err)
 
 	exists, err := fs.Exists(lib.LocalDir)
@@ -122,7 +321,7 @@ func TestCleanUpLib(t *testing.T) {
 func TestGetLocalPathInfo(t *testing.T) {
 	lib, container, _ := newTestLib("/path/to/my-lib")
 	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/gflowspkg.json", `{"files": []}`)
-	err := lib.Setup()
+	err := lib.Setup(nil)
 	assert.NoError(t, err)
 
 	localPath := filepath.Join(lib.LocalDir, "foo/bar.yml")
@@ -133,13 +332,14 @@ func TestGetLocalPathInfo(t *testing.T) {
 		LocalPath:   localPath,
 		SourcePath:  "/path/to/my-lib/foo/bar.yml",
 		Description: "my-lib/foo/bar.yml",
+		PackageName: "my-lib",
 	}, info)
 }
 
 func TestGetRemotePathInfo(t *testing.T) {
 	lib, _, roundTripper := newTestLib("https://example.com/path/to/my-lib")
 	roundTripper.StubBody("https://example.com/path/to/my-lib/gflowspkg.json", `{"files": []}`)
-	err := lib.Setup()
+	err := lib.Setup(nil)
 	assert.NoError(t, err)
 
 	localPath := filepath.Join(lib.LocalDir, "foo/bar.yml")
@@ -150,13 +350,14 @@ func TestGetRemotePathInfo(t *testing.T) {
 		LocalPath:   localPath,
 		SourcePath:  "https://example.com/path/to/my-lib/foo/bar.yml",
 		Description: "my-lib/foo/bar.yml",
+		PackageName: "my-lib",
 	}, info)
 }
 
 func TestGetPathInfoErrors(t *testing.T) {
 	lib, container, _ := newTestLib("/path/to/my-lib")
 	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/gflowspkg.json", `{"files": []}`)
-	err := lib.Setup()
+	err := lib.Setup(nil)
 	assert.NoError(t, err)
 
 	_, err = lib.GetPathInfo("foo/bar.yml")
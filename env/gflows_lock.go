@@ -0,0 +1,132 @@
+package env
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// LockFileName - the name of the lock file, in the context directory
+const LockFileName = "gflows.lock"
+
+// GFlowsLock - records the resolved source and content of each dependency, so that changes to
+// packages are detected rather than silently changing the generated workflows
+type GFlowsLock struct {
+	// Packages - the locked packages, keyed by the dependency path given in the config
+	Packages map[string]*GFlowsLockEntry `json:"packages"`
+}
+
+// GFlowsLockEntry - the resolved source and content of a package
+type GFlowsLockEntry struct {
+	// Name - the name of the package
+	Name string `json:"name"`
+
+	// Source - the resolved path to the package (including any git ref)
+	Source string `json:"source"`
+
+	// Commit - for git packages, the SHA of the commit installed
+	Commit string `json:"commit,omitempty"`
+
+	// Files - the SHA-256 of each installed file, keyed by its path relative to the package. Not
+	// recorded for local packages.
+	Files map[string]string `json:"files,omitempty"`
+
+	// Dependencies - the resolved paths of the packages the package depends on. Not recorded for
+	// local packages.
+	Dependencies []string `json:"dependencies,omitempty"`
+}
+
+// NewGFlowsLock - returns an empty lock
+func NewGFlowsLock() *GFlowsLock {
+	return &GFlowsLock{Packages: make(map[string]*GFlowsLockEntry)}
+}
+
+// ParseLock - parses the content of a lock file
+func ParseLock(content string) (*GFlowsLock, error) {
+	lock := NewGFlowsLock()
+	err := json.Unmarshal([]byte(content), lock)
+	if err != nil {
+		return nil, err
+	}
+	if lock.Packages == nil {
+		lock.Packages = make(map[string]*GFlowsLockEntry)
+	}
+	return lock, nil
+}
+
+// Serialize - returns the content of the lock file. Keys are sorted, so the output is stable.
+func (lock *GFlowsLock) Serialize() string {
+	content, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	return string(content) + "\n"
+}
+
+// NewGFlowsLockEntry - returns the entry for an installed package, hashing its files. Only the name
+// and source of local packages are recorded, since their content isn't fetched.
+func NewGFlowsLockEntry(fs *afero.Afero, lib *GFlowsLib) (*GFlowsLockEntry, error) {
+	if lib.isLocal() {
+		return &GFlowsLockEntry{Name: lib.PackageName, Source: lib.Path}, nil
+	}
+	files := make(map[string]string)
+	for _, file := range lib.Files {
+		relPath, err := filepath.Rel(lib.LocalDir, file.LocalPath)
+		if err != nil {
+			return nil, err
+		}
+		content, err := fs.ReadFile(file.LocalPath)
+		if err != nil {
+			return nil, err
+		}
+		files[filepath.ToSlash(relPath)] = fmt.Sprintf("%x", sha256.Sum256(content))
+	}
+	return &GFlowsLockEntry{
+		Name:         lib.PackageName,
+		Source:       lib.Path,
+		Commit:       lib.Commit,
+		Files:        files,
+		Dependencies: lib.Dependencies,
+	}, nil
+}
+
+// Diff - describes the differences between the locked entry and the installed one, or returns an
+// empty array if they match
+func (entry *GFlowsLockEntry) Diff(installed *GFlowsLockEntry) []string {
+	diffs := []string{}
+	if entry.Name != installed.Name {
+		diffs = append(diffs, fmt.Sprintf("name changed from %q to %q", entry.Name, installed.Name))
+	}
+	if entry.Source != installed.Source {
+		diffs = append(diffs, fmt.Sprintf("source changed from %s to %s", entry.Source, installed.Source))
+	}
+	if entry.Commit != installed.Commit {
+		diffs = append(diffs, fmt.Sprintf("commit changed from %s to %s", entry.Commit, installed.Commit))
+	}
+	if strings.Join(entry.Dependencies, ",") != strings.Join(installed.Dependencies, ",") {
+		diffs = append(diffs, fmt.Sprintf("dependencies changed from %v to %v", entry.Dependencies, installed.Dependencies))
+	}
+
+	fileDiffs := []string{}
+	for path, hash := range entry.Files {
+		installedHash, ok := installed.Files[path]
+		if !ok {
+			fileDiffs = append(fileDiffs, fmt.Sprintf("%s was removed", path))
+		} else if installedHash != hash {
+			fileDiffs = append(fileDiffs, fmt.Sprintf("%s changed", path))
+		}
+	}
+	for path := range installed.Files {
+		if _, ok := entry.Files[path]; !ok {
+			fileDiffs = append(fileDiffs, fmt.Sprintf("%s was added", path))
+		}
+	}
+	sort.Strings(fileDiffs)
+
+	return append(diffs, fileDiffs...)
+}
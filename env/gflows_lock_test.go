@@ -0,0 +1,71 @@
+package env
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSerializeLock(t *testing.T) {
+	lock := NewGFlowsLock()
+	lock.Packages["git@example.com:my/repo.git@v1"] = &GFlowsLockEntry{
+		Name:   "my-lib",
+		Source: "git@example.com:my/repo.git@v1",
+		Commit: "abc123",
+		Files:  map[string]string{"libs/b.yml": "bbb", "libs/a.yml": "aaa"},
+	}
+
+	content := lock.Serialize()
+
+	assert.Equal(t, `{
+  "packages": {
+    "git@example.com:my/repo.git@v1": {
+      "name": "my-lib",
+      "source": "git@example.com:my/repo.git@v1",
+      "commit": "abc123",
+      "files": {
+        "libs/a.yml": "aaa",
+        "libs/b.yml": "bbb"
+      }
+    }
+  }
+}
+`, content)
+	parsed, err := ParseLock(content)
+	assert.NoError(t, err)
+	assert.Equal(t, lock, parsed)
+}
+
+func TestParseLockErrors(t *testing.T) {
+	_, err := ParseLock("not json")
+	assert.Error(t, err)
+
+	lock, err := ParseLock("{}")
+	assert.NoError(t, err)
+	assert.Empty(t, lock.Packages)
+}
+
+func TestLockEntryDiff(t *testing.T) {
+	entry := &GFlowsLockEntry{
+		Name:   "my-lib",
+		Source: "git@example.com:my/repo.git",
+		Commit: "abc123",
+		Files:  map[string]string{"libs/a.yml": "aaa", "libs/b.yml": "bbb", "libs/c.yml": "ccc"},
+	}
+	installed := &GFlowsLockEntry{
+		Name:         "my-lib",
+		Source:       "git@example.com:my/repo.git",
+		Commit:       "def456",
+		Files:        map[string]string{"libs/a.yml": "aaa", "libs/b.yml": "xxx", "libs/d.yml": "ddd"},
+		Dependencies: []string{"git@example.com:my/repo.git/other-lib"},
+	}
+
+	assert.Empty(t, entry.Diff(entry))
+	assert.Equal(t, []string{
+		"commit changed from abc123 to def456",
+		"dependencies changed from [] to [git@example.com:my/repo.git/other-lib]",
+		"libs/b.yml changed",
+		"libs/c.yml was removed",
+		"libs/d.yml was added",
+	}, entry.Diff(installed))
+}
@@ -0,0 +1,108 @@
+package env
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/jbrunton/gflows/io"
+	"github.com/spf13/afero"
+)
+
+// PackageCache - stores installed remote and git packages in the user cache dir, keyed by their
+// source and resolved version (the commit for git packages, and a hash of their content
+// otherwise), so that they needn't be downloaded again on later runs
+type PackageCache struct {
+	fs  *afero.Afero
+	dir string
+}
+
+// NewPackageCache - creates a new package cache, stored in the user cache dir
+func NewPackageCache(fs *afero.Afero) *PackageCache {
+	return &PackageCache{
+		fs:  fs,
+		dir: io.CacheDir("packages"),
+	}
+}
+
+// Path - returns the directory for the package at the given version
+func (cache *PackageCache) Path(source string, version string) string {
+	return filepath.Join(cache.dir, fmt.Sprintf("%x", sha256.Sum256([]byte(source+"@"+version))))
+}
+
+// Exists - returns true if the package is cached at the given version
+func (cache *PackageCache) Exists(source string, version string) (bool, error) {
+	return cache.fs.Exists(cache.markerPath(cache.Path(source, version)))
+}
+
+// Store - copies the package installed in dir into the cache, and returns the location of the
+// cached copy
+func (cache *PackageCache) Store(dir string, source string, version string) (string, error) {
+	cacheDir := cache.Path(source, version)
+	exists, err := cache.Exists(source, version)
+	if err != nil || exists {
+		return cacheDir, err
+	}
+
+	// copy into a temp dir and then install it, since other processes may share the cache
+	err = cache.fs.MkdirAll(cache.dir, 0755)
+	if err != nil {
+		return "", err
+	}
+	tempDir, err := cache.fs.TempDir(cache.dir, ".installing-")
+	if err != nil {
+		return "", err
+	}
+	err = cache.fs.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		content, err := cache.fs.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		destination := filepath.Join(tempDir, relPath)
+		err = cache.fs.MkdirAll(filepath.Dir(destination), 0755)
+		if err != nil {
+			return err
+		}
+		return cache.fs.WriteFile(destination, content, 0644)
+	})
+	if err == nil {
+		err = cache.fs.WriteFile(cache.markerPath(tempDir), []byte{}, 0644)
+	}
+	if err != nil {
+		cache.fs.RemoveAll(tempDir)
+		return "", err
+	}
+	return cacheDir, io.InstallDir(cache.fs, tempDir, cacheDir, installedMarker)
+}
+
+// installedMarker - the file written once a package has been copied into the cache, so that
+// interrupted copies aren't used
+const installedMarker = ".gflows-installed"
+
+func (cache *PackageCache) markerPath(dir string) string {
+	return filepath.Join(dir, installedMarker)
+}
+
+// ContentVersion - returns the version of a package without a commit, given the hashes of its
+// files (keyed by path)
+func ContentVersion(files map[string]string) string {
+	paths := []string{}
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	hash := sha256.New()
+	for _, path := range paths {
+		fmt.Fprintf(hash, "%s %s\n", path, files[path])
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
@@ -1,7 +1,9 @@
 package fixtures
 
 import (
+	"crypto/sha1"
 	"fmt"
+	"os"
 	"path/filepath"
 
 	"github.com/spf13/afero"
@@ -10,14 +12,22 @@ import (
 type TestGitRepository = map[string]string
 
 type TestGitAdapter struct {
-	fs    *afero.Afero
-	repos map[string]*TestGitRepository
+	fs        *afero.Afero
+	repos     map[string]*TestGitRepository
+	refs      map[string]*TestGitRepository
+	revisions map[string]*TestGitRepository
+
+	// Fetches - the directories passed to Fetch
+	Fetches []string
 }
 
 func NewTestGitAdapter(fs *afero.Afero) *TestGitAdapter {
 	return &TestGitAdapter{
-		fs:    fs,
-		repos: make(map[string]*TestGitRepository),
+		fs:        fs,
+		repos:     make(map[string]*TestGitRepository),
+		refs:      make(map[string]*TestGitRepository),
+		revisions: make(map[string]*TestGitRepository),
+		Fetches:   []string{},
 	}
 }
 
@@ -26,6 +36,53 @@ func (gitAdapter *TestGitAdapter) Clone(url string, dir string) error {
 	if repo == nil {
 		return fmt.Errorf("Missing repo for %s", url)
 	}
+	// record the url where Checkout can find it, even if the clone is moved
+	err := gitAdapter.fs.WriteFile(filepath.Join(dir, ".git/url"), []byte(url), 0644)
+	if err != nil {
+		return err
+	}
+	return gitAdapter.writeFiles(dir, repo)
+}
+
+// Fetch - records the fetch
+func (gitAdapter *TestGitAdapter) Fetch(dir string) error {
+	gitAdapter.Fetches = append(gitAdapter.Fetches, dir)
+	return nil
+}
+
+// Checkout - replaces the files in dir with those stubbed for the ref (with StubRef), and returns
+// a fake commit SHA derived from the url and ref. HEAD refers to the files given to StubRepo.
+func (gitAdapter *TestGitAdapter) Checkout(dir string, ref string) (string, error) {
+	content, err := gitAdapter.fs.ReadFile(filepath.Join(dir, ".git/url"))
+	if err != nil {
+		return "", fmt.Errorf("Missing clone in %s", dir)
+	}
+	url := string(content)
+	commit := TestCommit(url, ref)
+	if ref == "HEAD" {
+		return commit, nil
+	}
+	repo := gitAdapter.refs[url+"@"+ref]
+	if repo == nil {
+		return "", fmt.Errorf("Missing ref %s for %s", ref, url)
+	}
+	files, err := gitAdapter.fs.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	for _, file := range files {
+		if file.Name() == ".git" {
+			continue
+		}
+		err = gitAdapter.fs.RemoveAll(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return "", err
+		}
+	}
+	return commit, gitAdapter.writeFiles(dir, repo)
+}
+
+func (gitAdapter *TestGitAdapter) writeFiles(dir string, repo *TestGitRepository) error {
 	for path, content := range *repo {
 		err := gitAdapter.fs.WriteFile(filepath.Join(dir, path), []byte(content), 0644)
 		if err != nil {
@@ -38,3 +95,33 @@ func (gitAdapter *TestGitAdapter) Clone(url string, dir string) error {
 func (gitAdapter *TestGitAdapter) StubRepo(url string, repo *TestGitRepository) {
 	gitAdapter.repos[url] = repo
 }
+
+// StubRef - stubs the files in the repository at the given ref
+func (gitAdapter *TestGitAdapter) StubRef(url string, ref string, repo *TestGitRepository) {
+	gitAdapter.refs[url+"@"+ref] = repo
+}
+
+// TestCommit - the fake commit SHA returned by TestGitAdapter.Checkout
+func TestCommit(url string, ref string) string {
+	return fmt.Sprintf("%x", sha1.Sum([]byte(url+"@"+ref)))
+}
+
+// ReadTree - writes the files stubbed for the revision (with StubRevision) to fs, and returns the
+// working directory as the root of the repository
+func (gitAdapter *TestGitAdapter) ReadTree(revision string, fs *afero.Afero) (string, error) {
+	files := gitAdapter.revisions[revision]
+	if files == nil {
+		return "", fmt.Errorf("Missing revision %s", revision)
+	}
+	for path, content := range *files {
+		err := fs.WriteFile(path, []byte(content), 0644)
+		if err != nil {
+			return "", err
+		}
+	}
+	return os.Getwd()
+}
+
+func (gitAdapter *TestGitAdapter) StubRevision(revision string, files *TestGitRepository) {
+	gitAdapter.revisions[revision] = files
+}
@@ -1,6 +1,11 @@
 package content
 
 import (
+	"crypto/sha256"
+	"fmt"
+	"path/filepath"
+	"sync"
+
 	"github.com/jbrunton/gflows/io"
 	"github.com/spf13/afero"
 )
@@ -9,13 +14,41 @@ import (
 type GitRepo struct {
 	Repository string
 	LocalDir   string
+
+	// Ref - the ref checked out (a tag, branch or commit SHA), or empty for the default branch
+	Ref string
+
+	// Commit - the SHA of the commit checked out
+	Commit string
+
+	// cached - true if LocalDir is in the repo cache, rather than a temp dir
+	cached bool
+}
+
+// RepoOptions - options for GetRepo
+type RepoOptions struct {
+	// Ref - the ref to check out (a tag, branch or commit SHA), or empty for the default branch
+	Ref string
+
+	// NoCache - if true then the repository is cloned into a temp dir rather than the repo cache
+	NoCache bool
+
+	// Offline - if true then a cached clone is used without fetching, and it's an error if there
+	// isn't one
+	Offline bool
 }
 
+// RepoManager - clones git repositories, one for each ref requested. Clones are kept in the user
+// cache dir and fetched on later runs, rather than cloned again. Safe for concurrent use.
 type RepoManager struct {
 	gitAdapter io.GitAdapter
 	fs         *afero.Afero
 	logger     *io.Logger
 	repos      map[string]*GitRepo
+	cacheDir   string
+
+	// mutex - guards repos. Held while cloning, so that each repository is only cloned once.
+	mutex sync.Mutex
 }
 
 func NewRepoManager(gitAdapter io.GitAdapter, fs *afero.Afero, logger *io.Logger) *RepoManager {
@@ -24,36 +57,125 @@ func NewRepoManager(gitAdapter io.GitAdapter, fs *afero.Afero, logger *io.Logger
 		fs:         fs,
 		logger:     logger,
 		repos:      make(map[string]*GitRepo),
+		cacheDir:   io.CacheDir("repos"),
 	}
 }
 
-func (manager *RepoManager) GetRepo(url string) (*GitRepo, error) {
-	repo := manager.repos[url]
+// GetRepo - clones (or fetches) the repository, if it hasn't already been for the ref, and checks
+// out the ref
+func (manager *RepoManager) GetRepo(url string, opts RepoOptions) (*GitRepo, error) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	key := url
+	if opts.Ref != "" {
+		key = url + "@" + opts.Ref
+	}
+	repo := manager.repos[key]
 	if repo != nil {
 		// already processed
 		return repo, nil
 	}
 
-	manager.logger.Printfln("Cloning %s...", url)
+	repo = &GitRepo{
+		Repository: url,
+		Ref:        opts.Ref,
+		cached:     !opts.NoCache,
+	}
+	if repo.cached {
+		repo.LocalDir = filepath.Join(manager.cacheDir, fmt.Sprintf("%x", sha256.Sum256([]byte(key))))
+	}
 
-	tempDir, err := manager.fs.TempDir("", "")
+	err := manager.updateRepo(repo, key, opts)
 	if err != nil {
 		return nil, err
 	}
+	manager.repos[key] = repo
 
-	repo = &GitRepo{
-		Repository: url,
-		LocalDir:   tempDir,
+	checkoutRef := opts.Ref
+	if checkoutRef == "" {
+		checkoutRef = "HEAD"
+	}
+	repo.Commit, err = manager.gitAdapter.Checkout(repo.LocalDir, checkoutRef)
+	if err != nil {
+		return repo, fmt.Errorf("unable to check out %s in %s: %s", checkoutRef, url, err)
 	}
-	manager.repos[url] = repo
+	manager.logger.Debugf("Checked out %s at %s (%s)\n", url, checkoutRef, repo.Commit)
+
+	return repo, nil
+}
+
+// updateRepo - fetches the repository if there's a cached clone, and clones it otherwise
+func (manager *RepoManager) updateRepo(repo *GitRepo, key string, opts RepoOptions) error {
+	if repo.cached {
+		exists, err := manager.fs.Exists(manager.markerPath(repo))
+		if err != nil {
+			return err
+		}
+		if exists {
+			if opts.Offline {
+				manager.logger.Debugf("Using cached clone of %s (%s)\n", key, repo.LocalDir)
+				return nil
+			}
+			manager.logger.Debugf("Fetching %s (%s)\n", key, repo.LocalDir)
+			return manager.gitAdapter.Fetch(repo.LocalDir)
+		}
+	}
+
+	if opts.Offline {
+		return fmt.Errorf("%s isn't cached, and can't be cloned with --offline", key)
+	}
+
+	manager.logger.Printfln("Cloning %s...", key)
+
+	if !repo.cached {
+		tempDir, err := manager.fs.TempDir("", "")
+		if err != nil {
+			return err
+		}
+		repo.LocalDir = tempDir
+		err = manager.gitAdapter.Clone(repo.Repository, repo.LocalDir)
+		if err != nil {
+			manager.fs.RemoveAll(repo.LocalDir)
+		}
+		return err
+	}
+
+	// clone into a temp dir and then install it, since other processes may share the cache
+	err := manager.fs.MkdirAll(manager.cacheDir, 0755)
+	if err != nil {
+		return err
+	}
+	tempDir, err := manager.fs.TempDir(manager.cacheDir, ".cloning-")
+	if err != nil {
+		return err
+	}
+	err = manager.gitAdapter.Clone(repo.Repository, tempDir)
+	if err == nil {
+		err = manager.fs.WriteFile(filepath.Join(tempDir, clonedMarker), []byte{}, 0644)
+	}
+	if err != nil {
+		manager.fs.RemoveAll(tempDir)
+		return err
+	}
+	return io.InstallDir(manager.fs, tempDir, repo.LocalDir, clonedMarker)
+}
 
-	err = manager.gitAdapter.Clone(url, tempDir)
+// clonedMarker - the file written once a cached repository has been cloned, so that interrupted
+// clones aren't used
+const clonedMarker = ".git/gflows-cloned"
 
-	return repo, err
+func (manager *RepoManager) markerPath(repo *GitRepo) string {
+	return filepath.Join(repo.LocalDir, clonedMarker)
 }
 
 func (manager *RepoManager) CleanUp() {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
 	for _, repo := range manager.repos {
+		if repo.cached {
+			continue
+		}
 		manager.logger.Debug("Removing temp directory", repo.LocalDir)
 		manager.fs.RemoveAll(repo.LocalDir)
 	}
@@ -2,9 +2,11 @@ package content
 
 import (
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/jbrunton/gflows/fixtures"
+	"github.com/jbrunton/gflows/io"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -15,19 +17,57 @@ func TestGetRepo(t *testing.T) {
 	repoManager := NewRepoManager(gitAdapter, container.FileSystem(), container.Logger())
 	gitAdapter.StubRepo("git@example.com:my/repo", &map[string]string{"example.txt": "foo bar"})
 
-	repo, err := repoManager.GetRepo("git@example.com:my/repo")
+	repo, err := repoManager.GetRepo("git@example.com:my/repo", RepoOptions{NoCache: true})
 
 	expectedOutput := "Cloning git@example.com:my/repo...\n"
 	assert.Equal(t, expectedOutput, out.String())
 	assert.NoError(t, err)
 
 	assert.Equal(t, repo.Repository, "git@example.com:my/repo")
+	assert.Equal(t, fixtures.TestCommit("git@example.com:my/repo", "HEAD"), repo.Commit)
 	exists, _ := fs.DirExists(repo.LocalDir)
 	assert.True(t, exists)
 	exists, _ = fs.Exists(filepath.Join(repo.LocalDir, "example.txt"))
 	assert.True(t, exists)
 }
 
+func TestGetRepoAtRef(t *testing.T) {
+	container, _, out := fixtures.NewTestContext("")
+	fs := container.FileSystem()
+	gitAdapter := container.GitAdapter().(*fixtures.TestGitAdapter)
+	repoManager := NewRepoManager(gitAdapter, container.FileSystem(), container.Logger())
+	gitAdapter.StubRepo("git@example.com:my/repo", &map[string]string{"example.txt": "v2"})
+	gitAdapter.StubRef("git@example.com:my/repo", "v1", &map[string]string{"example.txt": "v1"})
+
+	repo, err := repoManager.GetRepo("git@example.com:my/repo", RepoOptions{Ref: "v1", NoCache: true})
+	assert.NoError(t, err)
+	headRepo, err := repoManager.GetRepo("git@example.com:my/repo", RepoOptions{NoCache: true})
+	assert.NoError(t, err)
+	sameRepo, err := repoManager.GetRepo("git@example.com:my/repo", RepoOptions{Ref: "v1", NoCache: true})
+	assert.NoError(t, err)
+
+	expectedOutput := "Cloning git@example.com:my/repo@v1...\nCloning git@example.com:my/repo...\n"
+	assert.Equal(t, expectedOutput, out.String())
+	assert.Equal(t, repo, sameRepo)
+	assert.Equal(t, "v1", repo.Ref)
+	assert.Equal(t, fixtures.TestCommit("git@example.com:my/repo", "v1"), repo.Commit)
+	content, _ := fs.ReadFile(filepath.Join(repo.LocalDir, "example.txt"))
+	assert.Equal(t, "v1", string(content))
+	content, _ = fs.ReadFile(filepath.Join(headRepo.LocalDir, "example.txt"))
+	assert.Equal(t, "v2", string(content))
+}
+
+func TestGetRepoUnknownRef(t *testing.T) {
+	container, _, _ := fixtures.NewTestContext("")
+	gitAdapter := container.GitAdapter().(*fixtures.TestGitAdapter)
+	repoManager := NewRepoManager(gitAdapter, container.FileSystem(), container.Logger())
+	gitAdapter.StubRepo("git@example.com:my/repo", &map[string]string{})
+
+	_, err := repoManager.GetRepo("git@example.com:my/repo", RepoOptions{Ref: "v1", NoCache: true})
+
+	assert.EqualError(t, err, "unable to check out v1 in git@example.com:my/repo: Missing ref v1 for git@example.com:my/repo")
+}
+
 func TestCleanupRepo(t *testing.T) {
 	container, _, _ := fixtures.NewTestContext("")
 	fs := container.FileSystem()
@@ -35,7 +75,7 @@ func TestCleanupRepo(t *testing.T) {
 	repoManager := NewRepoManager(gitAdapter, container.FileSystem(), container.Logger())
 	gitAdapter.StubRepo("git@example.com:my/repo", &map[string]string{})
 
-	repo, err := repoManager.GetRepo("git@example.com:my/repo")
+	repo, err := repoManager.GetRepo("git@example.com:my/repo", RepoOptions{NoCache: true})
 	assert.NoError(t, err)
 
 	exists, _ := fs.DirExists(repo.LocalDir)
@@ -45,3 +85,50 @@ func TestCleanupRepo(t *testing.T) {
 	exists, _ = fs.DirExists(repo.LocalDir)
 	assert.False(t, exists)
 }
+
+func TestGetCachedRepo(t *testing.T) {
+	container, _, out := fixtures.NewTestContext("")
+	fs := container.FileSystem()
+	gitAdapter := container.GitAdapter().(*fixtures.TestGitAdapter)
+	gitAdapter.StubRepo("git@example.com:my/repo", &map[string]string{"example.txt": "foo bar"})
+
+	repo, err := NewRepoManager(gitAdapter, fs, container.Logger()).GetRepo("git@example.com:my/repo", RepoOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Cloning git@example.com:my/repo...\n", out.String())
+	assert.True(t, strings.HasPrefix(repo.LocalDir, io.CacheDir("repos")), "expected %s to be in the cache", repo.LocalDir)
+	exists, _ := fs.Exists(filepath.Join(repo.LocalDir, "example.txt"))
+	assert.True(t, exists)
+	assert.Empty(t, gitAdapter.Fetches)
+	entries, _ := fs.ReadDir(io.CacheDir("repos"))
+	assert.Len(t, entries, 1, "expected the clone to be installed from a temp dir")
+
+	// later runs fetch rather than clone
+	repoManager := NewRepoManager(gitAdapter, fs, container.Logger())
+	cachedRepo, err := repoManager.GetRepo("git@example.com:my/repo", RepoOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, repo.LocalDir, cachedRepo.LocalDir)
+	assert.Equal(t, []string{repo.LocalDir}, gitAdapter.Fetches)
+	assert.Equal(t, "Cloning git@example.com:my/repo...\n", out.String())
+
+	// cached clones aren't removed
+	repoManager.CleanUp()
+	exists, _ = fs.Exists(filepath.Join(repo.LocalDir, "example.txt"))
+	assert.True(t, exists)
+}
+
+func TestGetRepoOffline(t *testing.T) {
+	container, _, _ := fixtures.NewTestContext("")
+	fs := container.FileSystem()
+	gitAdapter := container.GitAdapter().(*fixtures.TestGitAdapter)
+	gitAdapter.StubRepo("git@example.com:my/repo", &map[string]string{"example.txt": "foo bar"})
+
+	_, err := NewRepoManager(gitAdapter, fs, container.Logger()).GetRepo("git@example.com:my/repo", RepoOptions{Offline: true})
+	assert.EqualError(t, err, "git@example.com:my/repo isn't cached, and can't be cloned with --offline")
+
+	_, err = NewRepoManager(gitAdapter, fs, container.Logger()).GetRepo("git@example.com:my/repo", RepoOptions{})
+	assert.NoError(t, err)
+	repo, err := NewRepoManager(gitAdapter, fs, container.Logger()).GetRepo("git@example.com:my/repo", RepoOptions{Offline: true})
+	assert.NoError(t, err)
+	assert.Equal(t, fixtures.TestCommit("git@example.com:my/repo", "HEAD"), repo.Commit)
+	assert.Empty(t, gitAdapter.Fetches)
+}
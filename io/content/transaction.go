@@ -0,0 +1,63 @@
+package content
+
+type fileBackup struct {
+	path    string
+	existed bool
+	content string
+}
+
+// Transaction - writes files, keeping a copy of their previous content so that the changes can be
+// rolled back
+type Transaction struct {
+	writer  *Writer
+	backups []fileBackup
+}
+
+// BeginTransaction - returns a new transaction for writing files
+func (writer *Writer) BeginTransaction() *Transaction {
+	return &Transaction{writer: writer}
+}
+
+// UpdateFileContent - updates the content of the destination (as Writer.UpdateFileContent does),
+// first backing up its current content so that it can be restored by Rollback
+func (transaction *Transaction) UpdateFileContent(destination string, content string, details string) error {
+	fs := transaction.writer.fs
+	backup := fileBackup{path: destination}
+	exists, err := fs.Exists(destination)
+	if err != nil {
+		return err
+	}
+	if exists {
+		data, err := fs.ReadFile(destination)
+		if err != nil {
+			return err
+		}
+		backup.existed = true
+		backup.content = string(data)
+	}
+	transaction.backups = append(transaction.backups, backup)
+	return transaction.writer.UpdateFileContent(destination, content, details)
+}
+
+// Rollback - restores the files written by the transaction to their previous state
+func (transaction *Transaction) Rollback() error {
+	fs := transaction.writer.fs
+	for i := len(transaction.backups) - 1; i >= 0; i-- {
+		backup := transaction.backups[i]
+		var err error
+		if backup.existed {
+			err = transaction.writer.SafelyWriteFile(backup.path, backup.content)
+		} else {
+			exists, _ := fs.Exists(backup.path)
+			if exists {
+				err = fs.Remove(backup.path)
+			}
+		}
+		if err != nil {
+			return err
+		}
+		transaction.writer.logger.Printfln("%11v %s", "rollback", backup.path)
+	}
+	transaction.backups = nil
+	return nil
+}
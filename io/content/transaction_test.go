@@ -0,0 +1,27 @@
+package content
+
+import (
+	"testing"
+
+	"github.com/jbrunton/gflows/fixtures"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionRollback(t *testing.T) {
+	container, _, out := fixtures.NewTestContext("")
+	fs := container.FileSystem()
+	writer := NewWriter(fs, container.Logger())
+	writer.SafelyWriteFile("path/to/existing", "foo")
+
+	transaction := writer.BeginTransaction()
+	assert.NoError(t, transaction.UpdateFileContent("path/to/existing", "foobar", ""))
+	assert.NoError(t, transaction.UpdateFileContent("path/to/new", "bar", ""))
+	err := transaction.Rollback()
+
+	assert.NoError(t, err)
+	content, _ := fs.ReadFile("path/to/existing")
+	assert.Equal(t, "foo", string(content))
+	exists, _ := fs.Exists("path/to/new")
+	assert.False(t, exists)
+	assert.Equal(t, "     update path/to/existing\n     create path/to/new\n   rollback path/to/new\n   rollback path/to/existing\n", out.String())
+}
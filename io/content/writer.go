@@ -42,6 +42,9 @@ func NewWriter(fs *afero.Afero, logger *io.Logger) *Writer {
 	}
 }
 
+// SafelyWriteFile - writes the content to the destination, creating the directory if needed. The
+// content is written to a temporary file which is then renamed, so that the destination is never
+// left partially written.
 func (writer *Writer) SafelyWriteFile(destination string, content string) error {
 	dir := filepath.Dir(destination)
 	if _, err := writer.fs.Stat(dir); err != nil {
@@ -52,8 +55,27 @@ func (writer *Writer) SafelyWriteFile(destination string, content string) error
 			}
 		}
 	}
-	err := writer.fs.WriteFile(destination, []byte(content), 0644)
-	return err
+
+	tempFile, err := writer.fs.TempFile(dir, "."+filepath.Base(destination)+".tmp")
+	if err != nil {
+		return err
+	}
+	tempPath := tempFile.Name()
+	_, err = tempFile.WriteString(content)
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = writer.fs.Chmod(tempPath, 0644)
+	}
+	if err == nil {
+		err = writer.fs.Rename(tempPath, destination)
+	}
+	if err != nil {
+		writer.fs.Remove(tempPath)
+		return err
+	}
+	return nil
 }
 
 // LogErrors - prints an error message for the given destination file, together with any additional
@@ -62,7 +84,9 @@ func (writer *Writer) LogErrors(destination string, message string, errors []str
 	writer.logger.PrintStatusErrors(errors, true)
 }
 
-func (writer *Writer) UpdateFileContent(destination string, content string, details string) {
+// UpdateFileContent - writes the content to the destination and logs the action taken. Files which
+// are identical aren't written, so that watchers aren't notified of spurious changes.
+func (writer *Writer) UpdateFileContent(destination string, content string, details string) error {
 	var action string
 	exists, _ := writer.fs.Exists(destination)
 	if exists {
@@ -75,15 +99,28 @@ func (writer *Writer) UpdateFileContent(destination string, content string, deta
 	} else {
 		action = "create"
 	}
-	err := writer.SafelyWriteFile(destination, content)
-	if err != nil {
-		panic(err)
+	if action != "identical" {
+		err := writer.SafelyWriteFile(destination, content)
+		if err != nil {
+			return err
+		}
 	}
 	if details != "" {
 		writer.logger.Printfln("%11v %s %s", action, destination, details)
 	} else {
 		writer.logger.Printfln("%11v %s", action, destination)
 	}
+	return nil
+}
+
+// DeleteFile - deletes the file at the destination, logging the given details
+func (writer *Writer) DeleteFile(destination string, details string) error {
+	err := writer.fs.Remove(destination)
+	if err != nil {
+		return err
+	}
+	writer.logger.Printfln("%11v %s %s", "delete", destination, details)
+	return nil
 }
 
 func (writer *Writer) ApplyGenerator(sourceFs http.FileSystem, contextDir string, generator WorkflowGenerator) error {
@@ -1,6 +1,7 @@
 package content
 
 import (
+	"os"
 	"strings"
 	"testing"
 
@@ -27,14 +28,31 @@ func TestSafelyWriteFile(t *testing.T) {
 
 	actualContent, _ := container.FileSystem().ReadFile("path/to/file")
 	assert.Equal(t, "foobar", string(actualContent))
+	// the temporary file is renamed to the destination
+	files, _ := container.FileSystem().ReadDir("path/to")
+	assert.Len(t, files, 1)
+}
+
+func TestDeleteFile(t *testing.T) {
+	container, _, out := fixtures.NewTestContext("")
+	writer := NewWriter(container.FileSystem(), container.Logger())
+	writer.SafelyWriteFile("path/to/file", "foobar")
+
+	err := writer.DeleteFile("path/to/file", "(baz)")
+
+	assert.NoError(t, err)
+	exists, _ := container.FileSystem().Exists("path/to/file")
+	assert.False(t, exists)
+	assert.Equal(t, "     delete path/to/file (baz)\n", out.String())
 }
 
 func TestUpdateFileContentCreate(t *testing.T) {
 	container, _, out := fixtures.NewTestContext("")
 	writer := NewWriter(container.FileSystem(), container.Logger())
 
-	writer.UpdateFileContent("path/to/file", "foobar", "(baz)")
+	err := writer.UpdateFileContent("path/to/file", "foobar", "(baz)")
 
+	assert.NoError(t, err)
 	actualContent, _ := container.FileSystem().ReadFile("path/to/file")
 	assert.Equal(t, "foobar", string(actualContent))
 	assert.Equal(t, "     create path/to/file (baz)\n", out.String())
@@ -45,8 +63,9 @@ func TestUpdateFileContentUpdate(t *testing.T) {
 	writer := NewWriter(container.FileSystem(), container.Logger())
 
 	writer.SafelyWriteFile("path/to/file", "foo")
-	writer.UpdateFileContent("path/to/file", "foobar", "(baz)")
+	err := writer.UpdateFileContent("path/to/file", "foobar", "(baz)")
 
+	assert.NoError(t, err)
 	actualContent, _ := container.FileSystem().ReadFile("path/to/file")
 	assert.Equal(t, "foobar", string(actualContent))
 	assert.Equal(t, "     update path/to/file (baz)\n", out.String())
@@ -57,11 +76,16 @@ func TestUpdateFileContentIdentical(t *testing.T) {
 	writer := NewWriter(container.FileSystem(), container.Logger())
 
 	writer.SafelyWriteFile("path/to/file", "foobar")
-	writer.UpdateFileContent("path/to/file", "foobar", "(baz)")
+	container.FileSystem().Chmod("path/to/file", 0444)
+	err := writer.UpdateFileContent("path/to/file", "foobar", "(baz)")
 
+	assert.NoError(t, err)
 	actualContent, _ := container.FileSystem().ReadFile("path/to/file")
 	assert.Equal(t, "foobar", string(actualContent))
 	assert.Equal(t, "  identical path/to/file (baz)\n", out.String())
+	// identical files aren't rewritten
+	info, _ := container.FileSystem().Stat("path/to/file")
+	assert.Equal(t, os.FileMode(0444), info.Mode().Perm())
 }
 
 func TestApplyGenerator(t *testing.T) {
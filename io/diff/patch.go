@@ -24,7 +24,47 @@ func NewPatch(filePatches []fdiff.FilePatch, message string) *Patch {
 
 // CreateFilePatch - returns a new FilePatch for the given difference
 func CreateFilePatch(wanted, got string) (fdiff.FilePatch, error) {
-	diffs := diff.Do(wanted, got)
+	return &textFilePatch{
+		chunks: createChunks(wanted, got),
+		from: object.ChangeEntry{
+			Name: wanted,
+		},
+		to: object.ChangeEntry{
+			Name: got,
+		},
+	}, nil
+}
+
+// CreateFileChangePatch - returns a FilePatch for a change to the file at the given path, with
+// headers that git apply accepts. If the file is created (or deleted) then from (or to) is nil.
+func CreateFileChangePatch(path string, from *string, to *string) fdiff.FilePatch {
+	patch := &textFilePatch{}
+	var fromContent, toContent string
+	if from != nil {
+		fromContent = *from
+		patch.from = newFileChangeEntry(path, fromContent)
+	}
+	if to != nil {
+		toContent = *to
+		patch.to = newFileChangeEntry(path, toContent)
+	}
+	patch.chunks = createChunks(fromContent, toContent)
+	return patch
+}
+
+func newFileChangeEntry(path string, content string) object.ChangeEntry {
+	return object.ChangeEntry{
+		Name: path,
+		TreeEntry: object.TreeEntry{
+			Name: path,
+			Mode: filemode.Regular,
+			Hash: plumbing.ComputeHash(plumbing.BlobObject, []byte(content)),
+		},
+	}
+}
+
+func createChunks(from, to string) []fdiff.Chunk {
+	diffs := diff.Do(from, to)
 	var chunks []fdiff.Chunk
 	for _, d := range diffs {
 
@@ -40,16 +80,7 @@ func CreateFilePatch(wanted, got string) (fdiff.FilePatch, error) {
 
 		chunks = append(chunks, &textChunk{d.Text, op})
 	}
-
-	return &textFilePatch{
-		chunks: chunks,
-		from: object.ChangeEntry{
-			Name: wanted,
-		},
-		to: object.ChangeEntry{
-			Name: got,
-		},
-	}, nil
+	return chunks
 }
 
 // textFilePatch is an implementation of fdiff.FilePatch interface
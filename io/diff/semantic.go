@@ -0,0 +1,139 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// ChangeType - the kind of a semantic change
+type ChangeType string
+
+const (
+	Added    ChangeType = "added"
+	Removed  ChangeType = "removed"
+	Modified ChangeType = "modified"
+)
+
+// Change - a structural change to a node in a tree of maps, lists and scalars (as parsed from
+// YAML or JSON)
+type Change struct {
+	Path string
+	Type ChangeType
+	From interface{}
+	To   interface{}
+}
+
+// String - describes the change, e.g. "jobs.build.steps[2].with.node-version: 14 -> 16" or
+// "jobs.lint added"
+func (change Change) String() string {
+	switch change.Type {
+	case Added:
+		return fmt.Sprintf("%s added", change.Path)
+	case Removed:
+		return fmt.Sprintf("%s removed", change.Path)
+	}
+	return fmt.Sprintf("%s: %s -> %s", change.Path, formatValue(change.From), formatValue(change.To))
+}
+
+// SemanticDiff - returns the changes between the two trees, ordered by path. Differences in
+// formatting (e.g. key order or quoting) don't appear since they aren't present in the trees. An
+// empty document (nil) is treated as an empty map, so each top level key is reported.
+func SemanticDiff(from interface{}, to interface{}) []Change {
+	return compareNodes("", emptyIfNil(from), emptyIfNil(to), []Change{})
+}
+
+func emptyIfNil(document interface{}) interface{} {
+	if document == nil {
+		return map[string]interface{}{}
+	}
+	return document
+}
+
+func compareNodes(path string, from interface{}, to interface{}, changes []Change) []Change {
+	fromMap, fromIsMap := from.(map[string]interface{})
+	toMap, toIsMap := to.(map[string]interface{})
+	if fromIsMap && toIsMap {
+		return compareMaps(path, fromMap, toMap, changes)
+	}
+
+	fromList, fromIsList := from.([]interface{})
+	toList, toIsList := to.([]interface{})
+	if fromIsList && toIsList {
+		return compareLists(path, fromList, toList, changes)
+	}
+
+	if !reflect.DeepEqual(from, to) {
+		changes = append(changes, Change{Path: rootPath(path), Type: Modified, From: from, To: to})
+	}
+	return changes
+}
+
+func compareMaps(path string, from map[string]interface{}, to map[string]interface{}, changes []Change) []Change {
+	keys := []string{}
+	for key := range from {
+		keys = append(keys, key)
+	}
+	for key := range to {
+		if _, ok := from[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		childPath := key
+		if path != "" {
+			childPath = path + "." + key
+		}
+		fromValue, inFrom := from[key]
+		toValue, inTo := to[key]
+		switch {
+		case !inFrom:
+			changes = append(changes, Change{Path: childPath, Type: Added, To: toValue})
+		case !inTo:
+			changes = append(changes, Change{Path: childPath, Type: Removed, From: fromValue})
+		default:
+			changes = compareNodes(childPath, fromValue, toValue, changes)
+		}
+	}
+	return changes
+}
+
+func compareLists(path string, from []interface{}, to []interface{}, changes []Change) []Change {
+	for i := 0; i < len(from) || i < len(to); i++ {
+		childPath := path + "[" + strconv.Itoa(i) + "]"
+		switch {
+		case i >= len(from):
+			changes = append(changes, Change{Path: childPath, Type: Added, To: to[i]})
+		case i >= len(to):
+			changes = append(changes, Change{Path: childPath, Type: Removed, From: from[i]})
+		default:
+			changes = compareNodes(childPath, from[i], to[i], changes)
+		}
+	}
+	return changes
+}
+
+func rootPath(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
+
+func formatValue(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		out, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprintf("%v", value)
+		}
+		return string(out)
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%v", value)
+}
@@ -0,0 +1,74 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSemanticDiff(t *testing.T) {
+	from := map[string]interface{}{
+		"on": "push",
+		"jobs": map[string]interface{}{
+			"build": map[string]interface{}{
+				"steps": []interface{}{
+					map[string]interface{}{"uses": "actions/checkout@v2"},
+					map[string]interface{}{"uses": "actions/setup-node@v1", "with": map[string]interface{}{"node-version": 14}},
+					map[string]interface{}{"run": "npm test"},
+				},
+			},
+			"old": map[string]interface{}{"runs-on": "ubuntu-latest"},
+		},
+	}
+	to := map[string]interface{}{
+		"jobs": map[string]interface{}{
+			"build": map[string]interface{}{
+				"steps": []interface{}{
+					map[string]interface{}{"uses": "actions/checkout@v2"},
+					map[string]interface{}{"uses": "actions/setup-node@v1", "with": map[string]interface{}{"node-version": 16}},
+				},
+			},
+			"lint": map[string]interface{}{"runs-on": "ubuntu-latest"},
+		},
+		"on": "push",
+	}
+
+	changes := SemanticDiff(from, to)
+
+	descriptions := []string{}
+	for _, change := range changes {
+		descriptions = append(descriptions, change.String())
+	}
+	assert.Equal(t, []string{
+		"jobs.build.steps[1].with.node-version: 14 -> 16",
+		"jobs.build.steps[2] removed",
+		"jobs.lint added",
+		"jobs.old removed",
+	}, descriptions)
+}
+
+func TestSemanticDiffTypeChange(t *testing.T) {
+	from := map[string]interface{}{"on": "push"}
+	to := map[string]interface{}{"on": []interface{}{"push", "pull_request"}}
+
+	changes := SemanticDiff(from, to)
+
+	assert.Equal(t, []Change{{Path: "on", Type: Modified, From: "push", To: []interface{}{"push", "pull_request"}}}, changes)
+	assert.Equal(t, `on: push -> ["push","pull_request"]`, changes[0].String())
+}
+
+func TestSemanticDiffIdentical(t *testing.T) {
+	tree := map[string]interface{}{"on": "push"}
+	assert.Empty(t, SemanticDiff(tree, tree))
+}
+
+func TestSemanticDiffEmptyDocument(t *testing.T) {
+	to := map[string]interface{}{"on": "push", "jobs": map[string]interface{}{}}
+
+	changes := SemanticDiff(nil, to)
+
+	assert.Equal(t, []Change{
+		{Path: "jobs", Type: Added, To: map[string]interface{}{}},
+		{Path: "on", Type: Added, To: "push"},
+	}, changes)
+}
@@ -1,6 +1,12 @@
 package io
 
-import "github.com/spf13/afero"
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/spf13/afero"
+)
 
 // CreateOsFs - creates an OS Afero instance
 func CreateOsFs() *afero.Afero {
@@ -10,6 +16,104 @@ func CreateOsFs() *afero.Afero {
 
 // CreateMemFs - creates an in-memory Afero instance for testing
 func CreateMemFs() *afero.Afero {
-	fs := afero.NewMemMapFs()
+	fs := &memFs{Fs: afero.NewMemMapFs()}
 	return &afero.Afero{Fs: fs}
 }
+
+// memFs - an in-memory filesystem which renames directories as the OS does (afero's MemMapFs only
+// renames the directory itself, leaving its contents at their old paths)
+type memFs struct {
+	afero.Fs
+}
+
+func (fs *memFs) Rename(oldname string, newname string) error {
+	info, err := fs.Stat(oldname)
+	if err != nil || !info.IsDir() {
+		return fs.Fs.Rename(oldname, newname)
+	}
+	if target, err := fs.Stat(newname); err == nil {
+		// directories may only replace empty directories
+		if !target.IsDir() {
+			return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: syscall.ENOTDIR}
+		}
+		entries, err := afero.ReadDir(fs.Fs, newname)
+		if err != nil {
+			return err
+		}
+		if len(entries) > 0 {
+			return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: syscall.ENOTEMPTY}
+		}
+	}
+
+	paths := []string{}
+	err = afero.Walk(fs.Fs, oldname, func(path string, info os.FileInfo, err error) error {
+		if err == nil {
+			paths = append(paths, path)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		relPath, err := filepath.Rel(oldname, path)
+		if err != nil {
+			return err
+		}
+		destination := filepath.Join(newname, relPath)
+		info, err := fs.Stat(path)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			err = fs.Fs.MkdirAll(destination, info.Mode().Perm())
+		} else {
+			err = fs.Fs.Rename(path, destination)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return fs.Fs.RemoveAll(oldname)
+}
+
+// InstallDir - moves tempDir to dir. Caches shared between processes should be written to a temp
+// dir in the same cache and then installed, so that processes never see (or remove) each other's
+// partial copies. The marker (a path relative to the directory) is the file written once a copy is
+// complete: if another process installed a complete copy first then that copy is kept.
+func InstallDir(fs *afero.Afero, tempDir string, dir string, marker string) error {
+	complete := func() (bool, error) {
+		return fs.Exists(filepath.Join(dir, marker))
+	}
+	installed, err := complete()
+	if err != nil || installed {
+		fs.RemoveAll(tempDir)
+		return err
+	}
+
+	// remove any partial copy written in place (e.g. by an older version of gflows)
+	err = fs.RemoveAll(dir)
+	if err != nil {
+		fs.RemoveAll(tempDir)
+		return err
+	}
+	err = fs.Rename(tempDir, dir)
+	if err != nil {
+		fs.RemoveAll(tempDir)
+		if installed, existsErr := complete(); existsErr == nil && installed {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// CacheDir - returns the directory for the given cache inside the user cache dir (falling back to
+// the temp dir if there isn't one)
+func CacheDir(name string) string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "gflows", name)
+}
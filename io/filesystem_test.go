@@ -0,0 +1,59 @@
+package io
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemFsRename(t *testing.T) {
+	fs := CreateMemFs()
+	fs.WriteFile("/tmp/source/nested/file.txt", []byte("foo"), 0644)
+	fs.WriteFile("/tmp/other/file.txt", []byte("bar"), 0644)
+
+	err := fs.Rename("/tmp/source", "/tmp/destination")
+
+	assert.NoError(t, err)
+	content, _ := fs.ReadFile("/tmp/destination/nested/file.txt")
+	assert.Equal(t, "foo", string(content))
+	exists, _ := fs.Exists("/tmp/source")
+	assert.False(t, exists)
+
+	// as on the OS, directories can't replace non-empty directories
+	err = fs.Rename("/tmp/destination", "/tmp/other")
+	assert.EqualError(t, err, "rename /tmp/destination /tmp/other: directory not empty")
+}
+
+func TestInstallDir(t *testing.T) {
+	fs := CreateMemFs()
+	writeCopy := func(dir string, content string, complete bool) {
+		fs.WriteFile(filepath.Join(dir, "file.txt"), []byte(content), 0644)
+		if complete {
+			fs.WriteFile(filepath.Join(dir, ".installed"), []byte{}, 0644)
+		}
+	}
+	assertInstalled := func(expectedContent string) {
+		content, _ := fs.ReadFile("/cache/dir/file.txt")
+		assert.Equal(t, expectedContent, string(content))
+		entries, _ := fs.ReadDir("/cache")
+		assert.Len(t, entries, 1, "expected temp dirs to be removed")
+	}
+
+	// installs the copy
+	writeCopy("/cache/.tmp-1", "foo", true)
+	assert.NoError(t, InstallDir(fs, "/cache/.tmp-1", "/cache/dir", ".installed"))
+	assertInstalled("foo")
+
+	// keeps a complete copy installed by another process
+	writeCopy("/cache/.tmp-2", "bar", true)
+	assert.NoError(t, InstallDir(fs, "/cache/.tmp-2", "/cache/dir", ".installed"))
+	assertInstalled("foo")
+
+	// replaces a partial copy
+	fs.RemoveAll("/cache/dir")
+	writeCopy("/cache/dir", "partial", false)
+	writeCopy("/cache/.tmp-3", "baz", true)
+	assert.NoError(t, InstallDir(fs, "/cache/.tmp-3", "/cache/dir", ".installed"))
+	assertInstalled("baz")
+}
@@ -1,15 +1,29 @@
 package io
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/spf13/afero"
 )
 
 type GitAdapter interface {
 	Clone(repo string, dir string) error
+	Fetch(dir string) error
+	Checkout(dir string, ref string) (string, error)
+	ReadTree(revision string, fs *afero.Afero) (string, error)
 }
 
+// abbreviatedHashPattern - matches abbreviated commit SHAs, which go-git can't resolve itself
+var abbreviatedHashPattern = regexp.MustCompile(`^[0-9a-f]{4,39}$`)
+
 type GoGitAdapter struct{}
 
 func NewGoGitAdapter() *GoGitAdapter {
@@ -17,9 +31,167 @@ func NewGoGitAdapter() *GoGitAdapter {
 }
 
 func (adapter *GoGitAdapter) Clone(url string, dir string) error {
-	_, err := git.PlainClone(dir, false, &git.CloneOptions{
+	repo, err := git.PlainClone(dir, false, &git.CloneOptions{
 		URL:      url,
 		Progress: os.Stdout,
 	})
+	if err != nil {
+		return err
+	}
+	// record the default branch as origin/HEAD, so that it can be resolved after later fetches
+	head, err := repo.Head()
+	if err != nil {
+		return err
+	}
+	if !head.Name().IsBranch() {
+		return nil
+	}
+	remoteHead := plumbing.NewRemoteHEADReferenceName("origin")
+	remoteBranch := plumbing.NewRemoteReferenceName("origin", head.Name().Short())
+	return repo.Storer.SetReference(plumbing.NewSymbolicReference(remoteHead, remoteBranch))
+}
+
+// Fetch - fetches branches and tags from origin into the repository cloned into dir. Local
+// branches aren't updated, so refs should be checked out with Checkout.
+func (adapter *GoGitAdapter) Fetch(dir string) error {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return err
+	}
+	err = repo.Fetch(&git.FetchOptions{
+		RemoteName: "origin",
+		Tags:       git.AllTags,
+		Force:      true,
+	})
+	if err == git.NoErrAlreadyUpToDate {
+		return nil
+	}
 	return err
 }
+
+// Checkout - checks out the given ref (a tag, branch or commit SHA) in the repository cloned into
+// dir, leaving the HEAD detached, and returns the SHA of the commit checked out
+func (adapter *GoGitAdapter) Checkout(dir string, ref string) (string, error) {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return "", err
+	}
+	hash, err := resolveRef(repo, ref)
+	if err != nil {
+		return "", err
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return "", err
+	}
+	err = worktree.Checkout(&git.CheckoutOptions{Hash: *hash, Force: true})
+	if err != nil {
+		return "", err
+	}
+	return hash.String(), nil
+}
+
+// resolveRef - resolves the ref to a commit. Refs are resolved against origin first, since local
+// branches (including HEAD) aren't updated by Fetch, and other branches only exist as remote
+// branches in a clone.
+func resolveRef(repo *git.Repository, ref string) (*plumbing.Hash, error) {
+	hash, remoteErr := repo.ResolveRevision(plumbing.Revision("origin/" + ref))
+	if remoteErr == nil {
+		return hash, nil
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err == nil {
+		return hash, nil
+	}
+	if abbreviatedHashPattern.MatchString(ref) {
+		return resolveAbbreviatedHash(repo, ref)
+	}
+	return nil, fmt.Errorf("unable to resolve ref %q: %s", ref, err)
+}
+
+func resolveAbbreviatedHash(repo *git.Repository, prefix string) (*plumbing.Hash, error) {
+	commits, err := repo.CommitObjects()
+	if err != nil {
+		return nil, err
+	}
+	var match *plumbing.Hash
+	err = commits.ForEach(func(commit *object.Commit) error {
+		if !strings.HasPrefix(commit.Hash.String(), prefix) {
+			return nil
+		}
+		if match != nil {
+			return fmt.Errorf("ambiguous ref %q", prefix)
+		}
+		hash := commit.Hash
+		match = &hash
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if match == nil {
+		return nil, fmt.Errorf("unable to resolve ref %q: %s", prefix, plumbing.ErrReferenceNotFound)
+	}
+	return match, nil
+}
+
+// ReadTree - writes the files in the repository containing the working directory, at the given
+// revision, to fs with paths relative to the root of the repository. Returns the root, so that the
+// fs can be mounted there (see MountFs) to look as the repository would if the revision were
+// checked out.
+func (adapter *GoGitAdapter) ReadTree(revision string, fs *afero.Afero) (string, error) {
+	workingDir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	repo, err := git.PlainOpenWithOptions(workingDir, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return "", err
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return "", err
+	}
+	repoDir, err := filepath.Abs(worktree.Filesystem.Root())
+	if err != nil {
+		return "", err
+	}
+	return repoDir, CopyTree(repo, revision, ".", fs)
+}
+
+// CopyTree - writes the files under the dir at the given revision of the repository to fs, with
+// paths relative to dir. Symlinks and submodules are skipped.
+func CopyTree(repo *git.Repository, revision string, dir string, fs *afero.Afero) error {
+	hash, err := repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return err
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return err
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return err
+	}
+	if dir != "." && dir != "" {
+		tree, err = tree.Tree(dir)
+		if err == object.ErrDirectoryNotFound {
+			// the directory didn't exist at this revision
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return tree.Files().ForEach(func(file *object.File) error {
+		if file.Mode != filemode.Regular && file.Mode != filemode.Executable && file.Mode != filemode.Deprecated {
+			return nil
+		}
+		content, err := file.Contents()
+		if err != nil {
+			return err
+		}
+		return fs.WriteFile(filepath.FromSlash(file.Name), []byte(content), 0644)
+	})
+}
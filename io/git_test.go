@@ -0,0 +1,198 @@
+package io
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func commitFiles(t *testing.T, repo *git.Repository, files map[string]string) {
+	worktree, err := repo.Worktree()
+	assert.NoError(t, err)
+	for path, content := range files {
+		file, err := worktree.Filesystem.Create(path)
+		assert.NoError(t, err)
+		_, err = file.Write([]byte(content))
+		assert.NoError(t, err)
+		assert.NoError(t, file.Close())
+		_, err = worktree.Add(path)
+		assert.NoError(t, err)
+	}
+	_, err = worktree.Commit("commit", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	assert.NoError(t, err)
+}
+
+func TestCopyTree(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	assert.NoError(t, err)
+	commitFiles(t, repo, map[string]string{
+		"README.md":                  "readme",
+		"project/.gflows/config.yml": "v1",
+	})
+	commitFiles(t, repo, map[string]string{
+		"project/.gflows/config.yml":         "v2",
+		"project/.gflows/workflows/test.yml": "test",
+	})
+
+	base := CreateMemFs()
+	assert.NoError(t, CopyTree(repo, "HEAD~1", "project", base))
+	head := CreateMemFs()
+	assert.NoError(t, CopyTree(repo, "HEAD", "project", head))
+
+	content, _ := base.ReadFile(".gflows/config.yml")
+	assert.Equal(t, "v1", string(content))
+	exists, _ := base.Exists(".gflows/workflows/test.yml")
+	assert.False(t, exists)
+	exists, _ = base.Exists("README.md")
+	assert.False(t, exists)
+
+	content, _ = head.ReadFile(".gflows/config.yml")
+	assert.Equal(t, "v2", string(content))
+	content, _ = head.ReadFile(".gflows/workflows/test.yml")
+	assert.Equal(t, "test", string(content))
+}
+
+func TestCopyTreeMissingDir(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	assert.NoError(t, err)
+	commitFiles(t, repo, map[string]string{"README.md": "readme"})
+
+	fs := CreateMemFs()
+	assert.NoError(t, CopyTree(repo, "HEAD", "project", fs))
+
+	files, _ := fs.ReadDir(".")
+	assert.Empty(t, files)
+}
+
+func TestCopyTreeUnknownRevision(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	assert.NoError(t, err)
+	commitFiles(t, repo, map[string]string{"README.md": "readme"})
+
+	err = CopyTree(repo, "no-such-branch", ".", CreateMemFs())
+	assert.Error(t, err)
+}
+
+// newBareRepo - creates a bare repository in dir with a v1.0.0 tag and a feature branch, and
+// returns the SHAs of the commits on master
+func newBareRepo(t *testing.T, dir string) []string {
+	sourceDir := filepath.Join(dir, "source")
+	bareDir := filepath.Join(dir, "repo.git")
+	repo, err := git.PlainInit(sourceDir, false)
+	assert.NoError(t, err)
+	_, err = git.PlainInit(bareDir, true)
+	assert.NoError(t, err)
+	worktree, err := repo.Worktree()
+	assert.NoError(t, err)
+
+	commitFiles(t, repo, map[string]string{"version.txt": "v1"})
+	v1, _ := repo.Head()
+	_, err = repo.CreateTag("v1.0.0", v1.Hash(), &git.CreateTagOptions{
+		Tagger:  &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+		Message: "v1.0.0",
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, worktree.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("feature"), Create: true}))
+	commitFiles(t, repo, map[string]string{"version.txt": "feature"})
+	assert.NoError(t, worktree.Checkout(&git.CheckoutOptions{Branch: plumbing.Master}))
+
+	commitFiles(t, repo, map[string]string{"version.txt": "v2"})
+	v2, _ := repo.Head()
+
+	_, err = repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{bareDir}})
+	assert.NoError(t, err)
+	err = repo.Push(&git.PushOptions{RefSpecs: []config.RefSpec{"refs/heads/*:refs/heads/*", "refs/tags/*:refs/tags/*"}})
+	assert.NoError(t, err)
+
+	return []string{v1.Hash().String(), v2.Hash().String()}
+}
+
+func TestCheckout(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gflows-git")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	commits := newBareRepo(t, dir)
+	adapter := NewGoGitAdapter()
+
+	scenarios := []struct {
+		ref             string
+		expectedContent string
+		expectedCommit  string
+	}{
+		{ref: "HEAD", expectedContent: "v2", expectedCommit: commits[1]},
+		{ref: "v1.0.0", expectedContent: "v1", expectedCommit: commits[0]},
+		{ref: "feature", expectedContent: "feature"},
+		{ref: commits[0], expectedContent: "v1", expectedCommit: commits[0]},
+		{ref: commits[0][:7], expectedContent: "v1", expectedCommit: commits[0]},
+	}
+
+	for i, scenario := range scenarios {
+		cloneDir := filepath.Join(dir, "clones", fmt.Sprintf("%d", i))
+		assert.NoError(t, adapter.Clone(filepath.Join(dir, "repo.git"), cloneDir))
+
+		commit, err := adapter.Checkout(cloneDir, scenario.ref)
+
+		assert.NoError(t, err, "Unexpected error for ref %q", scenario.ref)
+		if scenario.expectedCommit != "" {
+			assert.Equal(t, scenario.expectedCommit, commit, "Unexpected commit for ref %q", scenario.ref)
+		}
+		content, _ := ioutil.ReadFile(filepath.Join(cloneDir, "version.txt"))
+		assert.Equal(t, scenario.expectedContent, string(content), "Unexpected content for ref %q", scenario.ref)
+	}
+}
+
+func TestCheckoutUnknownRef(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gflows-git")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	newBareRepo(t, dir)
+	adapter := NewGoGitAdapter()
+	cloneDir := filepath.Join(dir, "clone")
+	assert.NoError(t, adapter.Clone(filepath.Join(dir, "repo.git"), cloneDir))
+
+	_, err = adapter.Checkout(cloneDir, "no-such-tag")
+
+	assert.EqualError(t, err, `unable to resolve ref "no-such-tag": reference not found`)
+}
+
+func TestFetch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gflows-git")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	newBareRepo(t, dir)
+	adapter := NewGoGitAdapter()
+	cloneDir := filepath.Join(dir, "clone")
+	assert.NoError(t, adapter.Clone(filepath.Join(dir, "repo.git"), cloneDir))
+
+	source, err := git.PlainOpen(filepath.Join(dir, "source"))
+	assert.NoError(t, err)
+	commitFiles(t, source, map[string]string{"version.txt": "v3"})
+	v3, _ := source.Head()
+	err = source.Push(&git.PushOptions{RefSpecs: []config.RefSpec{"refs/heads/*:refs/heads/*"}})
+	assert.NoError(t, err)
+
+	assert.NoError(t, adapter.Fetch(cloneDir))
+	commit, err := adapter.Checkout(cloneDir, "HEAD")
+
+	assert.NoError(t, err)
+	assert.Equal(t, v3.Hash().String(), commit)
+	content, _ := ioutil.ReadFile(filepath.Join(cloneDir, "version.txt"))
+	assert.Equal(t, "v3", string(content))
+
+	// fetching when up to date isn't an error
+	assert.NoError(t, adapter.Fetch(cloneDir))
+}
@@ -6,17 +6,27 @@ import (
 	"io"
 	"os/exec"
 	"strings"
+	"sync"
 )
 
+// Logger - writes output. Each call writes its output in one piece, and is safe for concurrent use
+// (e.g. when templates are evaluated in parallel).
 type Logger struct {
-	out          io.Writer
+	out io.Writer
+
+	// errOut - where warnings are written, so that they don't corrupt machine readable output
+	// (e.g. check --format json)
+	errOut io.Writer
+
 	enableColors bool
 	debug        bool
+	mutex        sync.Mutex
 }
 
-func NewLogger(out io.Writer, enableColors bool, debug bool) *Logger {
+func NewLogger(out io.Writer, errOut io.Writer, enableColors bool, debug bool) *Logger {
 	return &Logger{
 		out:          out,
+		errOut:       errOut,
 		enableColors: enableColors,
 		debug:        debug,
 	}
@@ -24,39 +34,46 @@ func NewLogger(out io.Writer, enableColors bool, debug bool) *Logger {
 
 func NewTestLogger() (*Logger, *bytes.Buffer) {
 	out := new(bytes.Buffer)
-	return NewLogger(out, false, false), out
+	return NewLogger(out, out, false, false), out
 }
 
 func (logger *Logger) Debug(a ...interface{}) (n int, err error) {
 	if logger.debug {
-		logger.Write([]byte("DEBUG: "))
-		return logger.Println(a...)
+		return logger.Write([]byte("DEBUG: " + fmt.Sprintln(a...)))
 	}
 	return 0, nil
 }
 
 func (logger *Logger) Debugf(format string, a ...interface{}) (n int, err error) {
 	if logger.debug {
-		logger.Write([]byte("DEBUG: "))
-		return logger.Printf(format, a...)
+		return logger.Write([]byte("DEBUG: " + fmt.Sprintf(format, a...)))
 	}
 	return 0, nil
 }
 
 func (logger *Logger) Write(p []byte) (n int, err error) {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
 	return logger.out.Write(p)
 }
 
 func (logger *Logger) Println(a ...interface{}) (n int, err error) {
-	return fmt.Fprintln(logger.out, a...)
+	return logger.Write([]byte(fmt.Sprintln(a...)))
 }
 
 func (logger *Logger) Printf(format string, a ...interface{}) (n int, err error) {
-	return fmt.Fprintf(logger.out, format, a...)
+	return logger.Write([]byte(fmt.Sprintf(format, a...)))
 }
 
 func (logger *Logger) Printfln(format string, a ...interface{}) (n int, err error) {
-	return fmt.Fprintf(logger.out, format+"\n", a...)
+	return logger.Write([]byte(fmt.Sprintf(format+"\n", a...)))
+}
+
+// Warnfln - writes a warning to the error output
+func (logger *Logger) Warnfln(format string, a ...interface{}) (n int, err error) {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+	return logger.errOut.Write([]byte(fmt.Sprintf("Warning: "+format+"\n", a...)))
 }
 
 func (logger *Logger) PrintStatusErrors(errors []string, firstLineOnly bool) {
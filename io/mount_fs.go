@@ -0,0 +1,104 @@
+package io
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// MountFs - an afero.Fs which serves paths inside a directory from one filesystem (with paths
+// relative to the directory), and all other paths from a base filesystem. Relative paths are
+// resolved against the working directory.
+type MountFs struct {
+	base    afero.Fs
+	dir     string
+	mounted afero.Fs
+}
+
+// NewMountFs - returns a filesystem with mounted in place of dir on base
+func NewMountFs(base afero.Fs, dir string, mounted afero.Fs) *MountFs {
+	return &MountFs{base: base, dir: filepath.Clean(dir), mounted: mounted}
+}
+
+// resolve - returns the filesystem for the given path, and the path within it
+func (fs *MountFs) resolve(name string) (afero.Fs, string) {
+	absPath, err := filepath.Abs(name)
+	if err != nil {
+		return fs.base, name
+	}
+	if absPath != fs.dir && !strings.HasPrefix(absPath, fs.dir+string(filepath.Separator)) {
+		return fs.base, name
+	}
+	relPath, err := filepath.Rel(fs.dir, absPath)
+	if err != nil {
+		return fs.base, name
+	}
+	return fs.mounted, relPath
+}
+
+func (fs *MountFs) Create(name string) (afero.File, error) {
+	target, path := fs.resolve(name)
+	return target.Create(path)
+}
+
+func (fs *MountFs) Mkdir(name string, perm os.FileMode) error {
+	target, path := fs.resolve(name)
+	return target.Mkdir(path, perm)
+}
+
+func (fs *MountFs) MkdirAll(name string, perm os.FileMode) error {
+	target, path := fs.resolve(name)
+	return target.MkdirAll(path, perm)
+}
+
+func (fs *MountFs) Open(name string) (afero.File, error) {
+	target, path := fs.resolve(name)
+	return target.Open(path)
+}
+
+func (fs *MountFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	target, path := fs.resolve(name)
+	return target.OpenFile(path, flag, perm)
+}
+
+func (fs *MountFs) Remove(name string) error {
+	target, path := fs.resolve(name)
+	return target.Remove(path)
+}
+
+func (fs *MountFs) RemoveAll(name string) error {
+	target, path := fs.resolve(name)
+	return target.RemoveAll(path)
+}
+
+func (fs *MountFs) Rename(oldname string, newname string) error {
+	oldTarget, oldPath := fs.resolve(oldname)
+	newTarget, newPath := fs.resolve(newname)
+	if oldTarget != newTarget {
+		return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: fmt.Errorf("can't rename across mounts")}
+	}
+	return oldTarget.Rename(oldPath, newPath)
+}
+
+func (fs *MountFs) Stat(name string) (os.FileInfo, error) {
+	target, path := fs.resolve(name)
+	return target.Stat(path)
+}
+
+func (fs *MountFs) Name() string {
+	return "MountFs"
+}
+
+func (fs *MountFs) Chmod(name string, mode os.FileMode) error {
+	target, path := fs.resolve(name)
+	return target.Chmod(path, mode)
+}
+
+func (fs *MountFs) Chtimes(name string, atime time.Time, mtime time.Time) error {
+	target, path := fs.resolve(name)
+	return target.Chtimes(path, atime, mtime)
+}
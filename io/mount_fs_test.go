@@ -0,0 +1,40 @@
+package io
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMountFs(t *testing.T) {
+	workingDir, _ := os.Getwd()
+	base := CreateMemFs()
+	mounted := CreateMemFs()
+	base.WriteFile("config.yml", []byte("from base"), 0644)
+	base.WriteFile("/outside/lib.yml", []byte("outside"), 0644)
+	mounted.WriteFile("config.yml", []byte("from mount"), 0644)
+	fs := &afero.Afero{Fs: NewMountFs(base.Fs, workingDir, mounted.Fs)}
+
+	content, err := fs.ReadFile("config.yml")
+	assert.NoError(t, err)
+	assert.Equal(t, "from mount", string(content))
+	content, err = fs.ReadFile(filepath.Join(workingDir, "config.yml"))
+	assert.NoError(t, err)
+	assert.Equal(t, "from mount", string(content))
+	content, err = fs.ReadFile("/outside/lib.yml")
+	assert.NoError(t, err)
+	assert.Equal(t, "outside", string(content))
+
+	assert.NoError(t, fs.WriteFile("/outside/new.yml", []byte("new"), 0644))
+	exists, _ := base.Exists("/outside/new.yml")
+	assert.True(t, exists, "expected writes outside the mount to go to the base fs")
+	assert.NoError(t, fs.WriteFile("new.yml", []byte("new"), 0644))
+	exists, _ = mounted.Exists("new.yml")
+	assert.True(t, exists, "expected writes inside the mount to go to the mounted fs")
+
+	err = fs.Rename("new.yml", "/outside/moved.yml")
+	assert.EqualError(t, err, "rename new.yml /outside/moved.yml: can't rename across mounts")
+}
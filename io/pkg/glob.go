@@ -0,0 +1,42 @@
+package pkg
+
+import (
+	gopath "path"
+	"strings"
+)
+
+// IsGlob - returns true if the path contains any glob metacharacters
+func IsGlob(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+// MatchGlob - returns true if the slash separated path matches the pattern. Patterns use the
+// syntax of path.Match within each path segment, and a "**" segment matches any number of
+// segments (including none), e.g. libs/**/*.libsonnet matches libs/a.libsonnet and
+// libs/common/b.libsonnet.
+func MatchGlob(pattern string, path string) (bool, error) {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(path, "/"))
+}
+
+func matchSegments(patterns []string, segments []string) (bool, error) {
+	if len(patterns) == 0 {
+		return len(segments) == 0, nil
+	}
+	if patterns[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			matched, err := matchSegments(patterns[1:], segments[i:])
+			if err != nil || matched {
+				return matched, err
+			}
+		}
+		return false, nil
+	}
+	if len(segments) == 0 {
+		return false, nil
+	}
+	matched, err := gopath.Match(patterns[0], segments[0])
+	if err != nil || !matched {
+		return false, err
+	}
+	return matchSegments(patterns[1:], segments[1:])
+}
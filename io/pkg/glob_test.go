@@ -0,0 +1,44 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsGlob(t *testing.T) {
+	assert.True(t, IsGlob("libs/*.libsonnet"))
+	assert.True(t, IsGlob("libs/**/steps.yml"))
+	assert.True(t, IsGlob("libs/step?.yml"))
+	assert.True(t, IsGlob("libs/[ab].yml"))
+	assert.False(t, IsGlob("libs/steps.yml"))
+}
+
+func TestMatchGlob(t *testing.T) {
+	scenarios := []struct {
+		pattern  string
+		path     string
+		expected bool
+	}{
+		{"libs/*.libsonnet", "libs/steps.libsonnet", true},
+		{"libs/*.libsonnet", "libs/common/steps.libsonnet", false},
+		{"libs/*.libsonnet", "libs/steps.yml", false},
+		{"libs/**/*.libsonnet", "libs/steps.libsonnet", true},
+		{"libs/**/*.libsonnet", "libs/common/steps.libsonnet", true},
+		{"libs/**/*.libsonnet", "libs/a/b/c/steps.libsonnet", true},
+		{"libs/**/*.libsonnet", "workflows/test.libsonnet", false},
+		{"libs/**", "libs/a/b.yml", true},
+		{"**/*.yml", "workflows/test/config.yml", true},
+		{"libs/step?.yml", "libs/steps.yml", true},
+	}
+	for _, scenario := range scenarios {
+		matched, err := MatchGlob(scenario.pattern, scenario.path)
+		assert.NoError(t, err)
+		assert.Equal(t, scenario.expected, matched, "Unexpected result for %q matching %q", scenario.path, scenario.pattern)
+	}
+}
+
+func TestMatchGlobErrors(t *testing.T) {
+	_, err := MatchGlob("libs/[a.yml", "libs/a.yml")
+	assert.EqualError(t, err, "syntax error in pattern")
+}
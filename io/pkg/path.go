@@ -13,11 +13,13 @@ func IsGitPath(path string) bool {
 	return strings.HasPrefix(path, "git@")
 }
 
-// ParseGitPath - returns the components of a Git path (the domain and the subdirectory)
-func ParseGitPath(path string) (string, string) {
-	r := regexp.MustCompile(`^(git@.*\.git)(.*)$`)
+// ParseGitPath - returns the components of a Git path (the repository, the subdirectory and the
+// ref), e.g. git@github.com:my-org/my-repo.git/my-lib@v1.2.0. The ref may be a tag, branch or
+// commit SHA, and is empty if not given.
+func ParseGitPath(path string) (string, string, string) {
+	r := regexp.MustCompile(`^(git@[^@]*\.git)([^@]*)(?:@(.+))?$`)
 	matches := r.FindStringSubmatch(path)
-	return matches[1], matches[2]
+	return matches[1], matches[2], matches[3]
 }
 
 // IsRemotePath - returns true if the path is a URL, false otherwise
@@ -4,4 +4,10 @@ type PathInfo struct {
 	SourcePath  string
 	LocalPath   string
 	Description string
+
+	// PackageName - the name of the package the file belongs to, or empty for files in the context
+	PackageName string
+
+	// Commit - the SHA of the commit installed, for files from git packages
+	Commit string
 }
@@ -13,13 +13,24 @@ func TestIsGitPath(t *testing.T) {
 }
 
 func TestParseGitPath(t *testing.T) {
-	repo, subdir := ParseGitPath("git@github.com:my-org/my-repo.git/my-lib")
-	assert.Equal(t, "git@github.com:my-org/my-repo.git", repo)
-	assert.Equal(t, "/my-lib", subdir)
-
-	repo, subdir = ParseGitPath("git@github.com:my-org/my-repo.git")
-	assert.Equal(t, "git@github.com:my-org/my-repo.git", repo)
-	assert.Equal(t, "", subdir)
+	scenarios := []struct {
+		path           string
+		expectedRepo   string
+		expectedSubdir string
+		expectedRef    string
+	}{
+		{"git@github.com:my-org/my-repo.git/my-lib", "git@github.com:my-org/my-repo.git", "/my-lib", ""},
+		{"git@github.com:my-org/my-repo.git", "git@github.com:my-org/my-repo.git", "", ""},
+		{"git@github.com:my-org/my-repo.git/my-lib@v1.2.0", "git@github.com:my-org/my-repo.git", "/my-lib", "v1.2.0"},
+		{"git@github.com:my-org/my-repo.git@feature/my-branch", "git@github.com:my-org/my-repo.git", "", "feature/my-branch"},
+		{"git@github.com:my-org/my-repo.git/my-lib@5f3a2c1", "git@github.com:my-org/my-repo.git", "/my-lib", "5f3a2c1"},
+	}
+	for _, scenario := range scenarios {
+		repo, subdir, ref := ParseGitPath(scenario.path)
+		assert.Equal(t, scenario.expectedRepo, repo, "Unexpected repo for %q", scenario.path)
+		assert.Equal(t, scenario.expectedSubdir, subdir, "Unexpected subdir for %q", scenario.path)
+		assert.Equal(t, scenario.expectedRef, ref, "Unexpected ref for %q", scenario.path)
+	}
 }
 
 func TestIsRemotePath(t *testing.T) {
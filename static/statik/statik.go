@@ -8,7 +8,7 @@ import (
 
 
 func init() {
-	data := "PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x12\x00	\x00config-schema.jsonUT\x05\x00\x01\x80Cm8\xd4\x94\xdfN\xf30\x0c\xc5\xef\xfb\x14\x91\xbf\xef\x12i\xf7\xbb\x85\x07\xe0\x15\xd2\xc6\xed\xcc2\xa7J=\xa6	\xed\xdd\xd1\xc6:\xba\xfci\x81\x0e\x04\xb7\x8e}\xdc\x9c_O^\n\xa5\xc0`MLB\x8e;X\xaacI)\xd89\xbf\xae\xad\xdb\xdd;\xae\xa9\xb9\xd4\x95\x02\xd9\xb7\x08K\x05\xae|\xc2J\xe0\xae\xaf\xb7\xde\xb5\xe8\x85\xb0\x1bt+\x05\xd5\n\xab\xf5u-\xaf2\xa6t:\xeb\xaa\x15ntT\x1fW\x9cR=\x9d#\xeb\xd2\xa2I\x1e\x0e\xe4K\xe7,j\x86\xa8\xe7\x10nT\n\xb6\x9e\xa6\xf4:\xf1\xc4MB.\xa8D\xf2\xa0\x8d9Q\xd3\xf6qx\xb5Z\xdb\x0e\x8b\x91Q\xa8\x1c\x0b\xb2\xfcJ\x13ox\xeb\"#\xf31\x89~\xfc2:=vn\x05\xc1Mk\xb5\xe0\xfc\xecX*\xb3\xc9\xd1\xde\xeb\xfdupHp\x93\xc8L\xfeW{\xf7h\xe0\x10\x18l\x91\x0dr\x15c\xfe\xae\xed_0\xbb8\xb7\xa7LM\x19\n\x0d\xc9j[>\x90O\x11\x19~]\xcf\xb1\x7f\x03\xbb9\x08\x91\x1bb\xcc\xd9\x18\x9ar\x8d\xa1\xd6[+\x11\x82\xff\x1e\xeb\xe3\xec\xbf\xc5\xe0\xe5^\x04\x0fvR\xd1=\xa3\xf7db\xaa\x19\xb7\x03\x92\x9f[<\x97o\x10\xa6?\x01!H\xfe\xcfA\xc8-\xbe\x11\x84\x89\xc6\xb7\x1c\x8e6\x1d\x8a\xd7\x00\x00\x00\xff\xffPK\x07\x08\n\x07\xe1\xaeN\x01\x00\x00l\x08\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x12\x00	\x00jsonnet/config.ymlUT\x05\x00\x01\x80Cm8$\xcc\xbb\x0e\x830\x0c\x85\xe1=Oa\x89\xcex\xcf\xd8\xa2R\xd6^\xe6\n*'\x98\x82\x8d\x12G\xbc~E\xd9\xcep\xfe\xaf\x82\x8bJ\xe0\x08\x81g\x82\xa0	\xda\xeb\xac[\xae]\x05\x0f\"\x18\xcd\xd6\xec\x11#\xdbX\x86\xfa\xa3\x0bNC*b*\x18\xc3\xfe\xc4\x8d\xbf\x8c\x87RRo\xac\xf2wt\xddg\xae\xdd\x916\x9c<\x9c\xda\xeey{\x9d\xdfMwwF\xcb:\xf7F\xd9;\x00\x92\xc8B\x1e\xa6\xac\"d\xee\x17\x00\x00\xff\xffPK\x07\x08\xd9\x11\x87n\x81\x00\x00\x00\x97\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x1a\x00	\x00jsonnet/libs/git.libsonnetUT\x05\x00\x01\x80Cm8\xaa\xe6RP\xc8M\xcc\xcc\x8bO*J\xccK\xce\xb0RPOI-K\xcd\xc9/P\xe7\xaa\xe5\x02\x04\x00\x00\xff\xffPK\x07\x082r\xa7\xff#\x00\x00\x00\x1d\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x1c\x00	\x00jsonnet/libs/steps.libsonnetUT\x05\x00\x01\x80Cm8T\x90OK\xc30\x00\xc5\xef\xf9\x14\x8f1\xc8\x06qC\x8f\x01a*\xee\x0f\xca&8\xf1Xb\x96\xb9\xda6\x19M\xda\x1eJ\xbf\xbb4Y\xbb\xf5\x12\xca\xeb\xef\xbd\xfcHM\x80\xd4H\x91\xc2:u\xb6x\x84U\xe9\x91\x11\x02\xe4\x85\x9eH\x93eB\x1f\xa6\x9c\xa3%}\xc8qI	\xd00\x02\x10@\x8bL\x1d&\xed\xc9p\xd3\xf1\x9b\xb3\xdb\xa1\xcbLKr\x7f\x86\x11\x02\x14V\xd9\x89\x90.6\xfaz]\x1br\x84\xb4'\xe5I\xc9\xc4\x14\xae\xdb\xf7M\x1a ;\xef\xfe.\xca\x07:\xf5\xbcU\xae8G\xbf\xc7\xd4Tv\xd8\xf9\xfb\xc9\x0b\xed\x8c\x9e{\xe4. \x8b\xf2\x9ev\x9eU\xecN\x9d\x0b\xe0L\xa24\xc7h\\\xd7\xb0J\xe6\xca\xd9\xd9j\xb3_\x7f=G\xfb\xdd\xdb\xeb\x16M3b\x9em\x86\xb2Qe\xf2dp\x7fx0Z\x8a4>\x08\xa7\xd0\x03\x94\x81\x06\x8fP\xedU\x94.\xaf&\xab\xe5\xfb\xee\xfb3z\xd9m\x97\x9b\x15\x07\x1d\x87\xaf\xe8\xe3i\xbf\xa6\xc1\x80\x05\x85\x86\xfc\x07\x00\x00\xff\xffPK\x07\x08\xdd\xc3\xd7\xf2\x0b\x01\x00\x00\xe3\x01\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00 \x00	\x00jsonnet/libs/workflows.libsonnetUT\x05\x00\x01\x80Cm8\x9c\x8d1\xaa\xc30\x10D\xfb=\xc5tn\x8c\x0f\xe0\xcf?I\x08Bv\xd6\xf2\xc2Zr\xa4u\x15t\xf7\x10\x12d\xd2\xa6\x9cy\xc3\x1bM\xb3W\x0417\xa7\xb8H\xc0?d\xdbS6tAlP\x99J\x8a\x91\xad\xfb#z\x10`YB\xe0\\F\xbc\x12\xb0\x1f\xaa.\xf3\xfd\xe0b\xee\xc6\x8b?\xd4\x1a\xfc\xc6g\x0bL\xd9\xc7y\xe52\xe2r~\x0f\x9b\x97\xe8\xde\xe8\xfa\x99\xd6\xbe\x99\xca\xfa\x9b\x81\x9a\xa7\xf6T\xe9\x19\x00\x00\xff\xffPK\x07\x08\xdb\x9a\x0eb\x81\x00\x00\x00\xf2\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00 \x00	\x00jsonnet/workflows/gflows.jsonnetUT\x05\x00\x01\x80Cm8l\x90\xb1N\xc30\x10\x86w?\xc5\x0dH\x06)\xcd\x03\x18u\x00\xc1\x82\x04\xcc\x08!\xcbI\x1d\xe3\xd6\xf1\x05\xdfY\x1dP\xdf\x1d\xc5M\xd3Pu\xf4\x7f\x9f\xef\xfft\x01[\x13\xc0y\xd6-\xc6\xce;X\x83\xef\x07L\x0c\xd2y\xae\x83o\x08c\xb4,\xef\xc5\x11%\xb6\x03-\xa8\xf2\xbe\xc2\xed1\xed\xba\x80\xfb%;g\xff\xf8\xe9C\xfbm\xdb\x9d\x9e\x11\xbd\xc5\x06\xd6\xf0+\x00d4\xbd\x95\n\xe4\xcd\xcb\xfb\xa3~{x}\x96\xd5\x18\xa7\x1ci\x85q\x9c\xe4&G\xce\xab`\xd8\x12\x97i\xf1R\xf0)\x00\xa6G]\x1a0s\xb5\xc8\xc8r\x1e\xb4+\x95\xd5%{\xb6\x11\x00_\xe20\xbb\x9e\xf2Ip\xf4S \x8f[J;Fu\xbe@\xcd\xc9;g\x13\xd5C\x0eA'\xfb\x93-\xb1\xde\xd8\xce\xe4\xc0\xa5v\x8b\x0d\xa9\xb2\x0b.\x0f\xa1\xae]F\x00\x1c\xaa\"D\xbc\xa9{\x13}g\x89?L\x1f\x9e\xb0\xbd=\xa1w\xe2/\x00\x00\xff\xffPK\x07\x08\xe3#\xd7n\xf8\x00\x00\x00\xdf\x01\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x0e\x00	\x00ytt/config.ymlUT\x05\x00\x01\x80Cm8$\xcc\xbb\x0e\x830\x0c\x85\xe1=Oa\x89\xcex\xcf\xd8\xa2R\xd6^\xe6\n*'\xb8\x05\x1b%\x8eP\xdf\xbe\xa2lg8\xffW\xc1I%p\x84\xc0\x13A\xd0\x04\xedy\xd25\xd7\xae\x82\x1b\x11\x8cfK\xf6\x88\x91m,C\xfd\xd2\x19\xdfC*b*\x18\xc3\xf6\xc4\x95?\x8c\xbbRRo\xac\xf2wt\xd9f\xae\xdd\x9e6\x9c<\x1c\xda\xee~y\x1c\x9fMwuF\xf32\xf5F\xd9;\x00\x92\xc8B\x1e\xbef\xee\x17\x00\x00\xff\xffPK\x07\x08S\xddb\xa7\x80\x00\x00\x00\x93\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x16\x00	\x00ytt/libs/steps.lib.ymlUT\x05\x00\x01\x80Cm8L\x8e\xcdJ\xc3@\x14F\xf7\xf3\x14\x1f$\x0b]\x8c\xe2vVQ1?(\x89`\xc4\xe5`\x93\x9b\xa6M;S\xe6'Y\x84\xbc{i\xa7-\xdd]8\x87{\xbe(AK\x1d,9\x7f\x90\xebn\xa7'\xfb\xf0(\x18\xe0-Y\x81\xed\xcax\xe5\xb4z>\x0b<\x08\xc9\xf8\xc2\x80i\xe3\xfa\x93\x088=\x90\x12\x88\xe7\x19\x96\x1aC\xce>eE\x9d\xff\xbe\xc9\xba\xfa\xfc(\xb1,,J@\xaae\x9csv)6=5\x83\x9c\xb4\x19\xee\xa2\xea\x7fO\" \xdc\x10\x03H\x8d!\x95\xa5_\xd5\xdf\x8f|\xaf\xca\xb4\xc8\x04\xe2p\xc8\xef\xd7:g\x80\xf1J l\x0cO\xae\xddc\x00\x00\x00\xff\xffPK\x07\x08nU\x00\xce\xaf\x00\x00\x00\xe6\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x13\x00	\x00ytt/libs/values.ymlUT\x05\x00\x01\x80Cm8RvHI,I\xd4/K\xcc)M-\xe6\xd2\xd5\xd5\xe5J\xcf,\xb1\xe2RP\xc8M\xcc\xcc\x8bO*J\xccK\xce\xb0RHI-K\xcd\xc9/\xe0\x02\x04\x00\x00\xff\xffPK\x07\x08\xe0M\xb2'4\x00\x00\x00.\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x1a\x00	\x00ytt/libs/workflows.lib.ymlUT\x05\x00\x01\x80Cm8\x9c\x8c\xb1\x0e\xc20\x0cD\xf7|\x85\xd5,\xad\x04\xf9\x80N\xf9\x93\xe8\xc0.\xadd\x02\xd4\x0e\x12\x7f\x8f\xdaN\xacL\xa7\xa7\xbb{1\x93>\xc0}\x97?\xee#\xc3\xd1\x9d\xa8\xdbs\x08!fb\x99\xe8\xd9T\xcb*\xaf&\xe6\x85eBS\xb7~\x18\x03\xfdT\x1b\x13]V\xd4\xeb,v\xd0\x996\x07\x1c\xe9\x0dmb\xe9\xb6x\xbac\xa9\xe5\xd8\xed\n\x9b\xff\xba\xc6LR9|\x03\x00\x00\xff\xffPK\x07\x08\xbd\xcf\x85\xf3u\x00\x00\x00\xc3\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x1f\x00	\x00ytt/workflows/gflows/gflows.ymlUT\x05\x00\x01\x80Cm8l\x8fAO\xc30\x0c\x85\xef\xfe\x15V\xca\xa1\x91\xe8\x908\xe6T\x90\xb8 \x01?!J;\x0f\xc6\xb2\xb8\xd46\x13\xff\x1e-E\x03\xa6\x1d\xfd\x9e\xdf\xe7\xe7\xa6\xc7\xcci\xdd\xba\x03\xcf\xbbM\xe6\x83\xac\xf2vX}\xed\xb3\xbbF7Y\xceq\xa6\x0f#\xd1\xb8\xa6M\xb2\xac\xe2<\x9cR\xa24\xfdK\x08\xa9M\xf1\xb5\x92\x8e\xf3\xf8F\xe3.\x9e\xe0\xce\x03\x94\xb4\xa7\x80\xcb\n\x80\xe3\xe2\x026=^\xbc\xd5z\x80w\x1e$\x00\xe2\x19\xea(!.\xb0\xab\xc7\x97\xfb\xf8|\xf7\xf4P\xb5\xd9\x8at\\\x02\xda`E\xad\xcbII\xb4Z\xb5\xef\x92\xec\xd0\x84$`\x1au\xcbEn*\x9eM\xfb\xcf\xdb\x1f\xbf\xe9\xf1\xef;\xad\xff\xd5\xcf\xba\xb4\x1e\xbe\x03\x00\x00\xff\xffPK\x07\x08\xd1\x93[#\xc3\x00\x00\x00I\x01\x00\x00PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\n\x07\xe1\xaeN\x01\x00\x00l\x08\x00\x00\x12\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81\x00\x00\x00\x00config-schema.jsonUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\xd9\x11\x87n\x81\x00\x00\x00\x97\x00\x00\x00\x12\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81\x97\x01\x00\x00jsonnet/config.ymlUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(2r\xa7\xff#\x00\x00\x00\x1d\x00\x00\x00\x1a\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81a\x02\x00\x00jsonnet/libs/git.libsonnetUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\xdd\xc3\xd7\xf2\x0b\x01\x00\x00\xe3\x01\x00\x00\x1c\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81\xd5\x02\x00\x00jsonnet/libs/steps.libsonnetUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\xdb\x9a\x0eb\x81\x00\x00\x00\xf2\x00\x00\x00 \x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x813\x04\x00\x00jsonnet/libs/workflows.libsonnetUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\xe3#\xd7n\xf8\x00\x00\x00\xdf\x01\x00\x00 \x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81\x0b\x05\x00\x00jsonnet/workflows/gflows.jsonnetUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(S\xddb\xa7\x80\x00\x00\x00\x93\x00\x00\x00\x0e\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81Z\x06\x00\x00ytt/config.ymlUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(nU\x00\xce\xaf\x00\x00\x00\xe6\x00\x00\x00\x16\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81\x1f\x07\x00\x00ytt/libs/steps.lib.ymlUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\xe0M\xb2'4\x00\x00\x00.\x00\x00\x00\x13\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81\x1b\x08\x00\x00ytt/libs/values.ymlUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\xbd\xcf\x85\xf3u\x00\x00\x00\xc3\x00\x00\x00\x1a\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81\x99\x08\x00\x00ytt/libs/workflows.lib.ymlUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\xd1\x93[#\xc3\x00\x00\x00I\x01\x00\x00\x1f\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81_	\x00\x00ytt/workflows/gflows/gflows.ymlUT\x05\x00\x01\x80Cm8PK\x05\x06\x00\x00\x00\x00\x0b\x00\x0b\x00g\x03\x00\x00x\n\x00\x00\x00\x00"
+	data := "PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x12\x00	\x00config-schema.jsonUT\x05\x00\x01\x80Cm8\xccUO\xef\xdb \x0c\xbd\xf3)\x90\xb7c\xa5\xde{\xdd\xee\xdb}\xda\x81\x04'a%\x10\x01iUM\xf9\xeeS\xaa4%.\xf9Su\xea\xef\xe7#\xc2\xcf\x8f\xf7l\xf3\x97q\x0e\x12\x0beTP\xd6x8\xf0\xfe\x88s8[w,\xb4=\x7f\xb3\xa6P\xe5x\xce9\x84K\x83p\xe0`\xb3?\x98\x07\xd8\xdd\xce\x1bg\x1btA\xe1\x1d\xa5\x0f8\xe2\xe5\x87\x93\xe8&\xa7\x11\x8e\x0fN\x99r\xc4\xe9\x03\xd0\xb45\x1c\xf8/h\x1czt'\x84\x1d\x87R\x85\xaa\xcd\xe0\xf7x\xb1\xbb\xe7@\x85b\xa1\x06\xe1\xba\xc4\xb7\x0f\x08X7Z\x04$\x9cS\xbc#\xc8	\xa3>\xa0\x12\xbeZ\xc2\xc8\xac\xd5(\x0c\x01a3\x80 \xa4\xbc\xfa$\xf4\xcfX\xecBh\x8f,\xc1\x01\xf2\n\xf3\xa3\x9fS\xfeYU|^a-\x96\xde\x93@\\\xd3z\xf0[d\x1ae\x02z].\xd2	\xb7\x80\xd6\xa95\xbc\xa1\xf5\x08\xdb\xa9\x03I\xf8mN$R!\xb7&\xa0		f\x1f/\"\x81$\xcd\xfc\xd4\xab\xd9\x0c\xcc6\x88\x8e\x11\x06\xebi\x03\xd9qr_\xdf[Ze\xb4[G\x0c\xe1\x9c\xb8LW\x96\nX?v\xf7B\xab\xdd5\x8a\x84\x06\x89\x0d\x1a\x89&W\xf8\xae\xea\x8c\xb0\xd8 6\x1bH\xa7DM	:\xec\xee\xef*\xde\xd0Im\xba\xdd\xf4\xff\xf1\xa9\x84\xad\x16\xa2)\x95\xa1K<Yv\"\xc0\xd5\x86B\xb4:PC\xe1\xab\xc3\xa2\xb7\xe0\xcb>\xfa5\xf7\xe4\xb3\x1c\x13bc\xed	\x9dS\xf2\xd1\xd5\x19\xb5\xe3;O\x17~\xd5_2L1\xe7Ok\x02\x99\xfc\xf7\x990W\xf8?\x99\xb02\x8d\xd7k\xcb\x97:\xf6o\x00PK\x07\x08\xe1\x90\x0e<\xa1\x01\x00\x00\xe8	\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x1b\x00	\x00github-workflow-schema.jsonUT\x05\x00\x01\x80Cm8\xec\x1c]\x93\xe46\xf1\xdd\xbf\xc2\xe5\xdc\xc3nn\xbd{9 \xc0\xbd\x84\x10(\x08E\x08\x15\x1e\xa8bg3\xa5\xb153\xba\x93%#\xc9\xfb\xc1f\xfe;%\x7f\xccHr\xcb\x96\xbd\x1e\x029\xfc\xb2kY\xfd\xa1Vw\xab\xd5R\xcfs\x14\xc7\xc9+\x99\xedq\x81\x92wq\xb2W\xaa|ws\xf3^r\x966\xad\xd7\\\xecnr\x81\xb6*}\xf3\xcb\x9b\xa6\xed\x93\xe4\xaa\x86#y\x07#[\xa0\xeb\xa6\x83T\\\xe0\x1arG\xd4\xbe\xda\xa4\x0f\\|\xd8R\xfep\xad;\xb5\xd0\x19/\n\xcc\x94F\xf1\xdb\x8a\xe5\x14\xe7q\xc6\xcb\xa7\x98oc\xb5\xc7\xf1\x1f\x88\xfac\xb5\x89;\xc8\xb8\xc1\x1c_\xdcc!	g\xf1\xdb7o\xdf\xa6o~qy\x1d\x7fW\xb1x\x95\xec4~\xd9u\xab\xca\x1c)\xbcJb\xc5\xe3\x0ce{\\\xe3\xa4Ha\xa9\xe2\x0e\xc5V\xf0\"\x9e\xc4~\xc3z\x8e\xb7\x84\x11E8\x93\xc9\xbbX\xcb0\x8e\x13$\xb2=Q8S\x95\xc0\xc7\xd68N\xd4S\xa9\xdf\x13\xa9\x04a\xbb\x1a\x81~\x12\xcc\xaa\"y\x17\xdf&_~\xf7\xcd\xcf\xde&Wq\xf2\xf8\xf9\xcf\xeb?\xbf\xfa<\xb9\xabq\x1e\x9a\xce\xc9F \x96\xedM\xa4\xaf\x04\xdej\xa4\x9f\xdc\x18\xbc\xdc\xec(\xdf\xc8\xc4\x02\xcd8\xcb*!0\xcb\x9e \xa6\xf8\xe6=\xce\xd4\x89\xa9R\xf0\x12\x0bE\xf0i`\xfaIv\x82W\xa5\xd5\xd4\x1f\xd9\xb1wK[?I\x86X\x86iJXZ\n\xbe\x13X\xda\x88\xe38\xe1\x0c\x7f\xab\xc7rk4\xc6\x16!\x8b\xd8\x86s\x8a\x11;Qs(\x82\xc0\xb0\xb8\xf0c\xa9\x19\"\x9c\xfd\xed\x89)\xf4\xe8\xe04\xde\xee\"\xb7\xf58\xc6D\xe0\x7fVD`m\n\xb7\xad\x9c\xee\x8e\x1fQ\x9e\xd7\xc4\x10\xfd\xab)\xd9-\xa2\x12\xbb\xf3\xb4%\xbbJ \xdd\xdb\x10\x11 \x9e\xa9s\x00\xf6gU\xb1\xc1\xe24\xe2\xc3\xd50\xfe\x9e\xd8\xc7\x00\x1c\xcd\x1a\x92\x86	\xef\x9d,[@\x06\xc4!\x98%$\x04z:\xea\xba~\x12\xa2p\xb1(\x0b-\x1e\xdb~3\xce\x14\"\x0c\x0b\x83\xd2d\x13$\x05\xda\x99\x8e%\xdc\x04\x05\xce1S\x04Q\xe9\x03\x87&\xcb\xe3\n\xf4\x93T\x12\x0b\x86\n\x97\x9dA\x96\x9c\x19\xd2OR\")\x1f\xb8\xc8'\xe3\x89\xa0\xff\xcdAcv\xef \xf59\x01v\x0fZARr\xa1\xbc\x02\x0bW\xa5\xbe\xfd\xf65\xd4A\xee\xdafot\xa3\x18\\\x8f\xdcb\xb0\x10\x9c|\x9a3/IA\xd8\xd7\xed`>\x8b\x80.\xc9=\xa7U\xd1\xd3\x8b\x19v\xe6\xe7w\"G\xbc\xb4W\xe3\x11\xfc\x87\xc8Aa{\xf1\xc6\xd4fx\xf1\x1coQE-\xb5\xf1\xd9\x98w\xa9\x15\x15\xf3\x0dc\xaa\x99\xca=\xa6\xd4A6`\nMw\x03\xb9\xa3\x18q\x9c\xe8@\x88\xb0]\x9a\x13\x813\xc5\x85\x19T\x8c\x18Z\x1f\xd4\xa6\x14y\xa8ju\xb4\xc4\xfeY\xc8\x9arZaM\xcd?\x0e\xc7\x8f4|\xc9.\xb1(\x88\x94\x8e\xdeM\\\xb0;\x86\xec\x90P`\x94\xa7\x88R\x1d\x0e>\x08\xa2p\xfdbD!'\xb0\xe7ht^\x0dFS|\xafC\xee\xc8\x95\xba\xbd^\xf5\x01\x8c\xf1M\xd5g\x94\x81\xa69\xce*\xc5\xf7\x98&\xd0\x98\x93l\x8f\xb3\x0f\x0b\xa3\xe4La\xa6\x96E\x9a\xe3\x92\xf2\xa7bq\xbc$O\x15\xff\x80\xd9\xb2H\xa5\xac\xf0\xb2|\xe6DfU\xcfD^\xcej\x89\xb2\x0fh\xb70\xb3\xe5\xf2\x18+JS\xbd\xb2`\xb9\xb0\x02\x08\\rI\xb4\x03\xd6\xfb*\xbd\x87[\x16\xbf\xc4Y%\x88zj\xfc\xc5\xc2\xb8\x15R\x95\\B\xd4\x91\xa3\xc4\xb3|w\xcb*\xe0\xe1\xda\xa8\xa4\xbfc\xd7\xee\xf9\xe8\x9a\xf5?\x8c3\xec\xec\xd9\xed\x004tU\x80Vx\xcf\xa0L\xd9y\xb7\xd16\x19\x87X?\xea\x9a\x1ag\xbe<R\x05\xb7\xf3\xa7\xa95wS\xee\x97i\x8b`3\xd8\xaf\x9a\x9d\x18a\xbb\xdf{7\xff\x1d\xed\xde|\x12\xc1Y\x9b\xb1\x9a\xbd\x1a\x02\xbb&\xef|\x98\xce\xb9\x124\x18,r\xc0\xed\xe0\xb6\xe6`Fl[{\x02\x83\x07\x97\xed\xab\xc8\x89b\xda\xf7c\xfej]\n\xae\xf3c\x84\xb3\xb5\xa8(>Bt\x0b\xfaZ\x87\xbe\xbdFY\xd5Ff4\x0b\x8c\xec\x96\x1cS\xec\xb6t\x0b/\xdc\xban\x9cP\x02\xaeUp\xeb\xba\xcbW\x1a_\xb7\\|0\xdfw\x9c\xd2\xaa0[\xeaE\x15\x02mW[\xa3+E\x1bL\xcd\x86\x02\x8b\x1d^7\xa97\xb3\x99P,\x95\xf68F\xa3^\xbc\xd6\x9b\x8a\xd0\xdcj\x15\xdc\xf1']\xd3:C\x02\xea\xba\xce\xf4\xde\xce\x12AYm(\xc9\xec\x16J\xd7\xed\xba\xe6k_\x0b|O\xf0\xc3\xc8gH2V7\x85\xc4\x0e;D\xe4\xde|\x17xG\xa4\x12O\xeb6(\xb0\xbfQ\x8c\xa4\xd5\xd4\x9f\xf8\x07\xa42\x0bc\x97r^g\x88R\xf0CNd\xe9\x85r\xb4\xf8\xb4T\x9f\xc0`\x0f\xa3\x0d\xec\xdb\xc6\x8f\xcc^9 \xe7\x01\xf6g\x15\xa5\xa3>\xcf\xf5\x91\x01\xd6_\"\xa5\xb0\xd0\x91i\xf2\xfdj\xf5j\xb5z^\xad\x9e/\xae\x7f\xb8]\xad\xc4j\xc5\xee.?]\xad\x0e\xab\xd5\xe1\x95\x9d\xa7\x1eu\xcf\x13I_\x7f:D\xfc\xfaS\x87|\x939\x07h\xd8\x89\x1e _\xe8s\x83\xda~	\xfb3f;\xb57\x12)G\x9f\xde\xcf\xb2\xb4\x9f\x92\x02e{\xc2\xcc\xdc\xdaxDB	\xab\x1eu$R\xa0\x8cK\xfd\xcf\x03a9\x7f\x90NT\xe2,?A\xa2\xbc]\xa3\xf4__\xa6\xff\xb8\xbbm\xfe\xbeI\x7f\xbdN\xef\\	\x96H\xcd;\xa1hB\xc9\x99\xea\xdeI\xb3sa\xa7\x05\xacC\xd8\xa2m\x96 \xe0\x8b7\x9am\x0f],4\x87+\xeb\xf5\x885%;\xc6\xadc\x9f\x05\x90+\xb4[\x98]\x8d\xf1,\xac\xea\xc9\x9f\xc0\xab\xee>,X\xddc:\xab\xe3x\xb5\xbe\x83\x8c\xc2\xf6\x1e\xc7^\xbb\xefA\x06F\xd3pv\xd5\x8el\x1d\xb0\xf1m\x8d3\xd4\xe7\x08\xe0/\xc4\xe1\xbb	Cpp-o=Yzd\xe8wtC\xc9\xc4A\xca\xef\xf9\xe6/\x18\xe7r\xc62\xd9\x9f\xdfig@\xb5\x035\xc4kOTo\xa8\x06\xd7=\x96B\x08\xc0\xd3T %\xc8\xe3\xfc \xc1\xe2\xb9]5-\xcd\xeaf\xa1\xc5\xfe\xfd\x05a?\xe0\xc7\xcb\x8cV9~e\xd0\x8dc/\xfd\xee\xb1Q\x0d\x0e}\xf8<\xd6\x11\xe5 ~\xffl\x8f\xccz\x0f\x83\xb3\xaf\xb3\x9f\xb0\xad\xf9\xe8\x94{\xcf\x15\xe3\xd8\xd5\x03\xf3\xb1ToX\x05}h\xee^\xe6\x80l\xd9\x01J\xe8\x9b\xa2\xd1	\x1a\x9c\x9eyb\x04\xc45$\xac^\xf7\xe7(\x90\x8f15>D\xbe)\xb0h\xf6\x8f(\"\xa0\xdfs4*\x17??\xb0w\x91\n\x9bW=&\xa78\x88{\x9c\xeb]\"\x8dq$\xc4\x8c\x02\x07|\x8a9\xe0\xf1L\xd2\xe1*\x0c\x18Jd\x85\xc2B\x0b\xff!\x82\x8c\xcc\xc08?\x11\x04dN}\x9c\x1c\xae\x82\x8e\x15\x87\xc0\x86\xd6\xe8\x01W\xde\x07\x03\x99\x82\x0e(\xc3\x0e'\xcd\xa1=\x10\xb5\x0fc\xcb{\xd8\xff\xf2\xeb\x02\xfa\xfc\x88\xb0\n\xa7\x9c\xa5X\x08.~\x0cu\x9e\xea\x00\xdc\xb0\x13<aL\x14)0\xafTZ\x10V),_h\xa7sLm\xc1qE\x0eE}F\x87Y\x8eY\xd6sd\x90\xee\xdf\xd6\x86tL\x9f\x1a:\xdc~\x89\x9c\x11\x8d\x85fVn\xb66np\x12\xfc0&\xd5\xd3\xa8g$x\x19\x17\x05\xa2\x7f\xe2\x1bCs}q\xd0\xe2\xf9m\xe6\xc4\xf3\x03\xd3~\x8c\xfe#@}\xcc\x93\x9d0t&\x00\x88QTL\xa6\xd6\xa5\xbc9j\xef\x0e\xde\xa12\x08\x0b\x87\xb1\x031\x92W\xe4 \xdd\xa1@h\xcc2G\xe2d\x9f\x9et\x0fx\xb1\xb4\x87\x1c\x92\x1d8\x8e.\x8b\x0eK\xc5;i\xdd\xd3\xe7/\x90\x11\x0f3\x90\xc4z\x83\xf3\xefQF\x03\xe1	\xdc\xd9\xfep\xb8\xf5.r\x1az\xb0\x87\xc8\xf7\x06:/\xcf\xe1\xd9\x7f\xca\x8c<\xab\x87\xc1\x93\x8dnt8\xbcRe\xd5?\x15\x1f\xb0\x85\xc0\x1d\x94\x7f\x943\xb6\x06\x0b\x045\xc0}\xb6\x01Oz\xec\x1d\x01\\\x7ft\x01\xbe\xdeFyU\xa4o\xf4\x1eo\xeeQ^\x8d\xfc\x05\xd7%\x7fR\x11\x9d%s\x81\x14\xde\xb9\xb7\x12\x07-s`\x85\xea'\xdaF\xa6\xa5\xedouv$\x91l\x11\xa1\xe9\x16I\xd7\x13\x0eH\x1e\xd2\xceq\xd3\xe8\xe9\x05<\x15\xb3\xa7\xc35\x03\xdb\x14\x00\xeaI\x81\x1e\xd3\x12	D)\xa6\x0b\x8e\x1e\xdaQ\xfc\x17\x0c>\xf2\xe8\x80\x1d\xf2\xb7*c\xec(\x82/\xb5~\x1c\xbbO\xa8xb\x8e\x97\x82\x82\xc8\xc3\xd50,\xac\x19'\x8e\xbc\x13\xeeqOX\xdc\x93\x0c\xcb)\xee),p\x98\xc0\xe8!\x02F\xef\xa9\x13\xfb\xd1\xe5|\xac]\xb3\xd1\xc1\x92\x8e\x9ca\xd9\x86\xd6m\xe3f\xec\x8c\x05\xae$\xdaP\xfc\xf7\xf6\x8a\xc7W\x88\xfe\x7f\x9f\xdc\xdb'\x7fl\x01^@&\xf6$o\xe7z\xca\xc5\xf5\xeb\xd5\xea\xe6\xf2\xf5\xc5\xf5\xeb\xcb\xd5\xea\xfa\xe2	}Q\xd0\xcb\x8b\xdf\\\xbf\xbe\xfc\xe2U\x02\x92[ \xcb)q&\xb0\x923\x0d<\x80X\x88\xad\x0f\x88\xc8\xbeeK\xd8\x1e\x0b\xa2\x8c|\x98\x1bq\xdcE\xc0L\xff\xef\x07\x81S\xeca4\xa6\x1a\n\x92\x0e\x91\x07\xef\x99B\x14\xa0\x12\xf9\xa7\xb4\xc2\xd4\x0ea\xc6\xf2\xd2\xdd7\xfc]{=\xf0kVV\xa6b\xf8\x12\x0b\xbe\xadK\x92c\x99	R:\xa5\xc4\x90\xf5E\x80Z\xe9\x8c\xb8\xc0Y]\x89\xfc\x0d\x96rf\xd9\xa9!\x1a\x90\x83\x9ev\xdb,\xd4\x85t\x9a\xae\xd9\xdcr\xff\x1c\x85\xf9\x93\xd3\x8d\xfd\xce\xd9\xe8K\xc5\x9cd\xf5\xa5\xfd\x8e\x81\xabcv\xe0\xca\xceT\xdd\xcd)5\x0c\xdf\xd9;\x8c\x1b\xc8Bv\xf3\x91\xd3u|\xcf\xa0A\xea\xee\x90\xde\x8c\xdd\x0c\xb4\xe3\xa1\x8a\xa5S\xfa[j\x08D\x05\xcf\xd1\xf82\xe3\xd4\xad]\xc1\xc0\xd3g!\x88\\\xd8\x94\x8c\xf0\x04\xa5\x05\x81\x99p.\x17\xf6n\xcd\xbb\xfd\x86\xc7\xd0\xde'\xb60[c\xb1.\xdf\x9f\x0bws\x87\xff\x0c\xd8\x9bR\x80\xe5\x11\xb7\x15\x05\xe7@|,L8'\xf2\xae\xbe\xe1\x0c4\x8e%}gE~,\x0cX\x9eH]\xb4\xb1<\xda\xb6\xf6cy\xc4v	\xc9\x99\xf0\xcb3\xccf}\x14w\x06\xbcf]\xcc\x19\xb0\x1f\xcbk\x96\xc7mT\xe9\x9c\x01y[\xecs6\xccM\xcd\xd0\x19\xd17\xa5Gg \xd0T0\x9d\x03\xf1\xa9T)\x1c\xbdn\x0cF\xdb\xd5Q\x05c\x9f\xc5|K\xe5\x8c^\x06\xaa\xeaZRbr\xbf \xba^MY0\xea`yt\xa5i\xcbc>\xdb\xd2\xdf\x14\xca-\xcf\xb0]owF\xfc\xc7\xfa;\x80F\x7fW\xd2=n\xd7\xb1\xa0>$\xb8\xef\x9e\x840\xe0&A\x1c\x03[\x1a`\xfb\x10\xc7\xfd\xf4\xa2\xb5\xf9\x83Xo	\xfb\xcb\xba\x86\xc0\xbcJ\x04\xe70\xbc\xe4\x0f\x91\xfd\xee1\xc4\xd33\xbe\xbd\x0d!q\x88\x02\x08B\xa3\xf7T\xd1\xf8\xf0\xde\x85\xe9c\xff\xa7~\x96Pw\xa8\xe0\xb4?\xa7/\xdd4\xea\xdfY\xcc=\xfbQ\xff.| \x1f\x12fV\xe3F\x95d\x823\xcf\xb7\xc1\xac\x8bo2{\x12\xee\x9f-\xd5$\x8f\x99\xbfyZ;\xe5R^\xe4\x01\x0b\xb3\x12\xf8\xda\xbf}[h,\xdb\xdfA\x017\x84<\xa0\xf6\xed\xa0\x0e\x1e>w\x1c\xcb\x11\xf9&\xd1\x97\x16\x8292i\x8f\x08\xe7\xbd\xa7<\xd8q\xca#\xc5T\xc1N\xd7\xb3\x1a\x05\xda\xf0\xe9\"\xaf\xd5\xdf\x10\xce\x84\x14\xb9\xef\xec\xd3\x82>D\x90\xef;D\x0e\xe13\xfe\xea\x16,\x89\xde\x99\xe5)\x07j\xd9/\xaf\xb3\xc0\xf5$\xd7V<\xcc\xce!\xfa\xf7\x00PK\x07\x08\xbb\xd6\x93^@\x0b\x00\x007W\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x12\x00	\x00jsonnet/config.ymlUT\x05\x00\x01\x80Cm8$\xcc\xbb\x0e\x830\x0c\x85\xe1=Oa\x89\xcex\xcf\xd8\xa2R\xd6^\xe6\n*'\x98\x82\x8d\x12G\xbc~E\xd9\xcep\xfe\xaf\x82\x8bJ\xe0\x08\x81g\x82\xa0	\xda\xeb\xac[\xae]\x05\x0f\"\x18\xcd\xd6\xec\x11#\xdbX\x86\xfa\xa3\x0bNC*b*\x18\xc3\xfe\xc4\x8d\xbf\x8c\x87RRo\xac\xf2wt\xddg\xae\xdd\x916\x9c<\x9c\xda\xeey{\x9d\xdfMwwF\xcb:\xf7F\xd9;\x00\x92\xc8B\x1e\xa6\xac\"d\xee7\x00PK\x07\x08\xd9\x11\x87n~\x00\x00\x00\x97\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x1a\x00	\x00jsonnet/libs/git.libsonnetUT\x05\x00\x01\x80Cm8\x00\x1d\x00\xe2\xff{\n  main_branch: 'develop'\n}\n\x03\x00PK\x07\x082r\xa7\xff$\x00\x00\x00\x1d\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x1c\x00	\x00jsonnet/libs/steps.libsonnetUT\x05\x00\x01\x80Cm8T\x90\xcfj21\x14\xc5\xf7y\x8a\x83\x08\x19!\x9f\xf2u\x19(\xd8\x96\xfa\x87\x16-\xd4\xd2\xe5\x90fb\xb5\x8e\x89L2\xe3B\xf2\xee%\x893v6\x97p\xee\xef\x9e{n.\x04(\x8d\x14%\xacS'\x8b{XUn\x19!@U\xebL\x9a\xe3Q\xe8b\xc49\x02\x19E\x8e\xabJ\x00\xcf\x08@\x00-\x8e\xaa\xc8Bem7\xccD\xcf\xf1_\xa3\xabM y\xac\xc9\x84\x00\xb5U6\x13\xd2\xed\x8d\xbe\xad\x0b\"GR;R\xee\x94<\x98\xda\xb5\xfe\x01\xcah\x82\xec\xa4\xedN\x9b;:\x8a\x87X\xe5\xeaS\xfe\xbd-\xcd\xd9\xf6g~\xbe\xaaZ;\xa3'\x11\xf9\x97\x90i\xf3\x9f\xb69\xcf{\xb7k\xb3\x00\xce\x1c\x94\xe6\x18\x0c/\x17X%+\xe5\xecx\xbe\xdc,>\x1e\xf3\xcd\xfa\xe5y\x05\xef\x07\xe1C\x00\xdf\x0f\x9b\x9fMu\xe8\xed\x0f\xa7\x17\x19mD\xb9/\x84S\xe8\x00\xca@S\x8etg\x17E\xe9\xe6\x96d>{]\x7f\xbe\xe7O\xeb\xd5l9\xe7\xa0\xc3\xf4\xca\xdf\x1e6\x0b\x1a\x19\xcf\x08\xe0\x19\xf1\xe4w\x00PK\x07\x08\xdd\xc3\xd7\xf2\x11\x01\x00\x00\xe3\x01\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00 \x00	\x00jsonnet/libs/workflows.libsonnetUT\x05\x00\x01\x80Cm8\x9c\x8d=\x0e\xc20\x0c\x85\xf7\x9c\xe2m]\xaa\x1e\xa0\x88\x93 \x14\xa5\xc5M-\xb9IH\x9c	\xe5\xee\x08\xa8\xa8X\xd9\xec\xf7\xbd\x1f\x89\xb3\x13xV;\xc7\xb0\xb0\xc7\x19\xbc\xa5\x98\x15\x9dg\x1d\x84\xa7\x12C \xedN\xc6<\x0c\xa0\x99\xbd\xa7\\F\xbc> U\x11\x9b\xe9^\xa9\xa8\xbd\xd1\xe2\xaa\xe8\x17\xfe\xe2C\x05\xa6\xec\xc2\xbcR\x19q9\xb6\x87\xcdq\xb0\x1ft\xdd\xad\xad\xdf\x8fT\xcb\xfa_\xc3;\xd2z\x03\xb4\xde4\xf3\x1c\x00PK\x07\x08\xdb\x9a\x0eb\x81\x00\x00\x00\xf2\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00 \x00	\x00jsonnet/workflows/gflows.jsonnetUT\x05\x00\x01\x80Cm8l\x90\xc1J\xc40\x10\x86\xefy\x8a9\x08Q\xd8\xed\x03D\xf6\xa0\xe8EP\xcf\"\x12\xd2n\x1a\xb3M353\xa1\x07\xd9w\x97fk\xad\xcb\x1e\xf3\xcf\xc7\xcc\x97?`c\x028\xcf\xba\xc1\xd8z\x07;\xf0\xfd\x80\x89A:\xcfU\xf05a\x8c\x96\xe5\xad8\xa1\xc4v\xa0\x15U\xde\x17\xb8\x11S\xd7\x06\x1c\xd7\xec\x92\xfd\xe3\xe7\xc5\xcd\xa7m:\xbd \xfa\x805\xec\xe0[\x00\xc8hz+\x15\xc8\xab\xa7\xd7{\xfdr\xf7\xfc(7S\x9cr\xa4-\xc6i\x92\xeb\x1c9o\x83aK\\\xa6\xc5K\xc1\xbb\x00\x98\x1fU\xb9\x80\x997\xab\x8c,\xe7A\xbbb\xba\xce\xcfl\x04\xc0\x878.\xae\xbf\x96\xb3\xe0\xe4\xa7@\x9e\xb6\x94\xeb\x18\xd5_\x03\x15'\xef\x9cMT\x0d9\x04\x9d\xecW\xb6\xc4zo[\x93\x03\x97\xb3\x07\xacI\x95\xcf\xc2y\x11\xeaR3\x02\xe0\xb8)B\xc4\xfb\xaa7\xd1\xb7\x96\xf8\xcd\xf4\xe1\x01\x9b\xeb\x11S\xd7\x06\x1co\xc4\xcf\x00PK\x07\x08\xe3#\xd7n\xf8\x00\x00\x00\xdf\x01\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x0e\x00	\x00ytt/config.ymlUT\x05\x00\x01\x80Cm8$\xcc\xbb\x0e\x830\x0c\x85\xe1=Oa\x89\xcex\xcf\xd8\xa2R\xd6^\xe6\n*'\xb8\x05\x1b%\x8eP\xdf\xbe\xa2lg8\xffW\xc1I%p\x84\xc0\x13A\xd0\x04\xedy\xd25\xd7\xae\x82\x1b\x11\x8cfK\xf6\x88\x91m,C\xfd\xd2\x19\xdfC*b*\x18\xc3\xf6\xc4\x95?\x8c\xbbRRo\xac\xf2wt\xd9f\xae\xdd\x9e6\x9c<\x1c\xda\xee~y\x1c\x9fMwuF\xf32\xf5F\xd9;\x00\x92\xc8B\x1e\xbef\xee7\x00PK\x07\x08S\xddb\xa7}\x00\x00\x00\x93\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x16\x00	\x00ytt/libs/steps.lib.ymlUT\x05\x00\x01\x80Cm8L\x8e\xbdJ\xc5@\x10F\xfby\x8a\x0fr\x0b-V\xb1\xddjU\xbc\xb9AI\x04#\x96\x8b&\x13\xa3\xd1Y\xd9\x9f\xa4\x08yw\xd1\x15\xb9\xdd\xc09\xcc\xf9\n\x83\x9e\x07\x04\x8e\xe9\xcb\xbe\x0e\x1fn	'\xa7\x9a\x80\x148h\xbc\xbf\xf8$\xd1\xc9\xf9\xaf\xa0\xb2`\xe6\x0b\x02\x96\xb78\xfe\x88@t\x13\x8b\xc6n]\x11\xb8\xf3\x1c\xc3YY\xb5\x87\xc7+\xdb6\xb775\xb6\x8d\n\x03\x96\x9e\x94R\xf4W\xecF\xee&\xbb8?\x1dE\xe5\xf9\x93uF\xf8G\x04\xb0\xcc9U\xee\xef\x9a\xa7\x07{\xdd\xd4\xfb\xaa\xd4\xd8\xe5\xc3\xde_\xb6\x07\x02|\x12\x8d\xbc1?\xa1\xc2\x80\xa5\xa7\xef\x01\x00PK\x07\x08nU\x00\xce\xad\x00\x00\x00\xe6\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x13\x00	\x00ytt/libs/values.ymlUT\x05\x00\x01\x80Cm8\x00.\x00\xd1\xff#@data/values\n---\ngit:\n  main_branch: develop\n\x03\x00PK\x07\x08\xe0M\xb2'5\x00\x00\x00.\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x1a\x00	\x00ytt/libs/workflows.lib.ymlUT\x05\x00\x01\x80Cm8\x9c\x8c\xb1\x0e\xc20\x0cD\xf7|\x85\xd5,\xad\x04\xf9\x80N\xf9\x93\xe8\xc0.\xadd\x02\xd4\x0e\x12\x7f\x8f\xdaN\xacL\xa7\xa7\xbb{1\x93>\xc0}\x97?\xee#\xc3\xd1\x9d\xa8\xdbs\x08!fb\x99\xe8\xd9T\xcb*\xaf&\xe6\x85eBS\xb7~\x18\x03\xfdT\x1b\x13]V\xd4\xeb,v\xd0\x996\x07\x1c\xe9\x0dmb\xe9\xb6x\xbac\xa9\xe5\xd8\xed\n\x9b\xff\xba\xc6LR9|\x07\x00PK\x07\x08\xbd\xcf\x85\xf3r\x00\x00\x00\xc3\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x1f\x00	\x00ytt/workflows/gflows/gflows.ymlUT\x05\x00\x01\x80Cm8l\x8fAO\xc30\x0c\x85\xef\xfe\x15V\xca\xa1\x91\xe8\x908\xe6T\x90\xb8 \x01?!J;\x0f\xc6\xb2\xb8\xd46\x13\xff\x1e-E\x03\xa6\x1d\xfd\x9e\xdf\xe7\xe7\xa6\xc7\xcci\xdd\xba\x03\xcf\xbbM\xe6\x83\xac\xf2vX}\xed\xb3\xbbF7Y\xceq\xa6\x0f#\xd1\xb8\xa6M\xb2\xac\xe2<\x9cR\xa24\xfdK\x08\xa9M\xf1\xb5\x92\x8e\xf3\xf8F\xe3.\x9e\xe0\xce\x03\x94\xb4\xa7\x80\xcb\n\x80\xe3\xe2\x026=^\xbc\xd5z\x80w\x1e$\x00\xe2\x19\xea(!.\xb0\xab\xc7\x97\xfb\xf8|\xf7\xf4P\xb5\xd9\x8at\\\x02\xda`E\xad\xcbII\xb4Z\xb5\xef\x92\xec\xd0\x84$`\x1au\xcbEn*\x9eM\xfb\xcf\xdb\x1f\xbf\xe9\xf1\xef;\xad\xff\xd5\xcf\xba\xb4\x1e\xbe\x07\x00PK\x07\x08\xd1\x93[#\xc0\x00\x00\x00I\x01\x00\x00PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\xe1\x90\x0e<\xa1\x01\x00\x00\xe8	\x00\x00\x12\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xb4\x81\x00\x00\x00\x00config-schema.jsonUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\xbb\xd6\x93^@\x0b\x00\x007W\x00\x00\x1b\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81\xea\x01\x00\x00github-workflow-schema.jsonUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\xd9\x11\x87n~\x00\x00\x00\x97\x00\x00\x00\x12\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xb4\x81|\x0d\x00\x00jsonnet/config.ymlUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(2r\xa7\xff$\x00\x00\x00\x1d\x00\x00\x00\x1a\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xb4\x81C\x0e\x00\x00jsonnet/libs/git.libsonnetUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\xdd\xc3\xd7\xf2\x11\x01\x00\x00\xe3\x01\x00\x00\x1c\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xb4\x81\xb8\x0e\x00\x00jsonnet/libs/steps.libsonnetUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\xdb\x9a\x0eb\x81\x00\x00\x00\xf2\x00\x00\x00 \x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xb4\x81\x1c\x10\x00\x00jsonnet/libs/workflows.libsonnetUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\xe3#\xd7n\xf8\x00\x00\x00\xdf\x01\x00\x00 \x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xb4\x81\xf4\x10\x00\x00jsonnet/workflows/gflows.jsonnetUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(S\xddb\xa7}\x00\x00\x00\x93\x00\x00\x00\x0e\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xb4\x81C\x12\x00\x00ytt/config.ymlUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(nU\x00\xce\xad\x00\x00\x00\xe6\x00\x00\x00\x16\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xb4\x81\x05\x13\x00\x00ytt/libs/steps.lib.ymlUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\xe0M\xb2'5\x00\x00\x00.\x00\x00\x00\x13\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xb4\x81\xff\x13\x00\x00ytt/libs/values.ymlUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\xbd\xcf\x85\xf3r\x00\x00\x00\xc3\x00\x00\x00\x1a\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xb4\x81~\x14\x00\x00ytt/libs/workflows.lib.ymlUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\xd1\x93[#\xc0\x00\x00\x00I\x01\x00\x00\x1f\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xb4\x81A\x15\x00\x00ytt/workflows/gflows/gflows.ymlUT\x05\x00\x01\x80Cm8PK\x05\x06\x00\x00\x00\x00\x0c\x00\x0c\x00\xb9\x03\x00\x00W\x16\x00\x00\x00\x00"
 		fs.Register(data)
 	}
 	
\ No newline at end of file
@@ -2,7 +2,6 @@ package action
 
 import (
 	"github.com/jbrunton/gflows/env"
-	"github.com/jbrunton/gflows/io"
 	"github.com/jbrunton/gflows/io/content"
 	"github.com/jbrunton/gflows/workflow"
 
@@ -15,6 +14,8 @@ type Container struct {
 	env             *env.GFlowsEnv
 	installer       *env.GFlowsLibInstaller
 	workflowManager *WorkflowManager
+	validator       *workflow.Validator
+	buildCache      *workflow.BuildCache
 }
 
 func (container *Container) Context() *config.GFlowsContext {
@@ -28,7 +29,8 @@ func (container *Container) WorkflowManager() *WorkflowManager {
 			container.Context(),
 			container.ContentWriter(),
 			container.Environment(),
-			container.Logger())
+			container.Logger(),
+			container.BuildCache())
 		container.workflowManager = NewWorkflowManager(
 			container.FileSystem(),
 			container.Logger(),
@@ -66,12 +68,27 @@ func (container *Container) Installer() *env.GFlowsLibInstaller {
 	return container.installer
 }
 
-func (container *Container) GitAdapter() io.GitAdapter {
-	return io.NewGoGitAdapter()
+func (container *Container) Validator() *workflow.Validator {
+	if container.validator == nil {
+		schemaCache := workflow.NewSchemaCache(
+			container.FileSystem(),
+			container.ContentReader(),
+			container.Logger(),
+			container.Context())
+		container.validator = workflow.NewValidator(container.FileSystem(), schemaCache, container.BuildCache(), container.Context())
+	}
+	return container.validator
+}
+
+func (container *Container) BuildCache() *workflow.BuildCache {
+	if container.buildCache == nil {
+		container.buildCache = workflow.NewBuildCache(container.FileSystem(), container.Logger(), container.Context())
+	}
+	return container.buildCache
 }
 
-func (container *Container) Validator() *workflow.Validator {
-	return workflow.NewValidator(container.FileSystem(), container.Context())
+func (container *Container) SchemaManager() *SchemaManager {
+	return NewSchemaManager(container.Context(), container.ContentReader(), container.ContentWriter())
 }
 
 func (container *Container) Watcher() *Watcher {
@@ -0,0 +1,23 @@
+package action
+
+import (
+	"github.com/jbrunton/gflows/workflow"
+)
+
+// GetWorkflowDependencies - returns the files read when evaluating the named workflows (i.e. the
+// templates and everything they import), sorted. Files read before an evaluation error are
+// included, so this works for invalid workflows too.
+func (manager *WorkflowManager) GetWorkflowDependencies(names []string) ([]string, error) {
+	definitions, err := manager.getNamedDefinitions(names)
+	if err != nil {
+		return nil, err
+	}
+
+	dependencies := workflow.DependencySet{}
+	for _, definition := range definitions {
+		for _, path := range definition.Dependencies {
+			dependencies.Add(path)
+		}
+	}
+	return dependencies.Paths(), nil
+}
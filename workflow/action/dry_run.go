@@ -0,0 +1,91 @@
+package action
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jbrunton/gflows/io/diff"
+	"github.com/jbrunton/gflows/workflow"
+
+	fdiff "github.com/go-git/go-git/v5/plumbing/format/diff"
+)
+
+// DiffWorkflows - returns a unified diff (which git apply accepts) of the changes UpdateWorkflows
+// would make to the workflows selected by the filter, and of the deletions PruneWorkflows would
+// make if prune is true. Nothing is written. Errors for invalid workflows are logged as they are by
+// UpdateWorkflows. As with UpdateWorkflows, if any workflows are invalid then there are no changes
+// unless partial is true, in which case the diff for the valid workflows is returned along with an
+// error.
+func (manager *WorkflowManager) DiffWorkflows(filter *workflow.WorkflowFilter, prune bool, partial bool) (string, error) {
+	definitions, err := manager.getSelectedDefinitions(filter)
+	if err != nil {
+		return "", err
+	}
+
+	valid := true
+	filePatches := []fdiff.FilePatch{}
+	for _, definition := range definitions {
+		details := fmt.Sprintf("(from %s)", definition.Description)
+		if !definition.Status.Valid {
+			manager.contentWriter.LogErrors(definition.Destination, details, definition.Status.Errors)
+			valid = false
+			continue
+		}
+		schemaResult := manager.validator.ValidateSchema(definition)
+		if !schemaResult.Valid {
+			manager.contentWriter.LogErrors(definition.Destination, details, schemaResult.Errors)
+			valid = false
+			continue
+		}
+
+		current, err := manager.readWorkflow(definition.Destination)
+		if err != nil {
+			return "", err
+		}
+		if current != nil && *current == definition.Content {
+			continue
+		}
+		content := definition.Content
+		filePatches = append(filePatches, diff.CreateFileChangePatch(definition.Destination, current, &content))
+	}
+
+	if prune {
+		gitHubWorkflows, err := manager.GetWorkflows()
+		if err != nil {
+			return "", err
+		}
+		for _, gitHubWorkflow := range gitHubWorkflows {
+			if gitHubWorkflow.Ownership != workflow.Orphaned || !filter.Matches(gitHubWorkflow.Name()) {
+				continue
+			}
+			current, err := manager.readWorkflow(gitHubWorkflow.Path)
+			if err != nil {
+				return "", err
+			}
+			filePatches = append(filePatches, diff.CreateFileChangePatch(gitHubWorkflow.Path, current, nil))
+		}
+	}
+
+	if !valid && !partial {
+		return "", errors.New("errors encountered generating workflows")
+	}
+	patch := diff.NewPatch(filePatches, "").Format()
+	if !valid {
+		return patch, errors.New("errors encountered generating workflows")
+	}
+	return patch, nil
+}
+
+// readWorkflow - returns the content of the workflow at the given path, or nil if it doesn't exist
+func (manager *WorkflowManager) readWorkflow(path string) (*string, error) {
+	exists, err := manager.fs.Exists(path)
+	if err != nil || !exists {
+		return nil, err
+	}
+	data, err := manager.fs.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	content := string(data)
+	return &content, nil
+}
@@ -0,0 +1,125 @@
+package action
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Annotation - a GitHub Actions workflow command which annotates a file with an error or warning.
+// See https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions
+type Annotation struct {
+	Level   string
+	File    string
+	Line    int
+	Col     int
+	Title   string
+	Message string
+}
+
+// String - formats the annotation as a workflow command
+func (annotation *Annotation) String() string {
+	properties := []string{fmt.Sprintf("file=%s", escapeAnnotationProperty(annotation.File))}
+	if annotation.Line > 0 {
+		properties = append(properties, fmt.Sprintf("line=%d", annotation.Line))
+	}
+	if annotation.Col > 0 {
+		properties = append(properties, fmt.Sprintf("col=%d", annotation.Col))
+	}
+	if annotation.Title != "" {
+		properties = append(properties, fmt.Sprintf("title=%s", escapeAnnotationProperty(annotation.Title)))
+	}
+	return fmt.Sprintf("::%s %s::%s", annotation.Level, strings.Join(properties, ","), escapeAnnotationData(annotation.Message))
+}
+
+// GetAnnotations - returns annotations for the errors and warnings in the report. Template and
+// schema errors point at the template source, content errors at the generated workflow.
+func (report *WorkflowReport) GetAnnotations() []*Annotation {
+	annotations := []*Annotation{}
+	for _, err := range report.TemplateErrors {
+		annotation := &Annotation{Level: "error", File: report.Source, Title: "gflows: template error", Message: err}
+		annotation.Line, annotation.Col = findSourcePosition(report.Source, err)
+		annotations = append(annotations, annotation)
+	}
+	for _, err := range report.SchemaErrors {
+		annotation := &Annotation{Level: "error", File: report.Source, Title: "gflows: invalid schema", Message: err}
+		if file, line, col := findErrorPosition(err); file != "" {
+			annotation.File, annotation.Line, annotation.Col = file, line, col
+		}
+		annotations = append(annotations, annotation)
+	}
+	if report.Content != nil {
+		title, hint := "gflows: out of date", "Run \"gflows update\" to update."
+		switch report.Status {
+		case StatusOrphaned:
+			title, hint = "gflows: orphaned", "Run \"gflows prune\" to delete it."
+		case StatusHandEdited:
+			title, hint = "gflows: edited by hand", fmt.Sprintf("Make the changes in the template (%s) instead.", report.Description)
+		}
+		for _, err := range report.Content.Errors {
+			message := fmt.Sprintf("%s. %s", err, hint)
+			annotations = append(annotations, &Annotation{Level: "error", File: report.Destination, Title: title, Message: message})
+		}
+	}
+	for _, warning := range report.Warnings {
+		annotations = append(annotations, &Annotation{Level: "warning", File: report.Source, Title: "gflows", Message: warning})
+	}
+	return annotations
+}
+
+func formatAnnotations(reports []*WorkflowReport) string {
+	var builder strings.Builder
+	for _, report := range reports {
+		for _, annotation := range report.GetAnnotations() {
+			builder.WriteString(annotation.String())
+			builder.WriteString("\n")
+		}
+	}
+	return builder.String()
+}
+
+// findSourcePosition - looks for a reference to the source file in the error (as given in jsonnet
+// errors, e.g. "path/to/template.jsonnet:12:3-8") and returns the line and column, or zero values
+// if there isn't one
+func findSourcePosition(source string, err string) (int, int) {
+	r := regexp.MustCompile(regexp.QuoteMeta(source) + `:(\d+)(?::(\d+))?`)
+	matches := r.FindStringSubmatch(err)
+	if matches == nil {
+		return 0, 0
+	}
+	var line, col int
+	fmt.Sscan(matches[1], &line)
+	if matches[2] != "" {
+		fmt.Sscan(matches[2], &col)
+	}
+	return line, col
+}
+
+var errorPositionPattern = regexp.MustCompile(`^([^\s:]+):(\d+)(?::(\d+))?: `)
+
+// findErrorPosition - returns the file, line and column from the "file:line:col: " prefix added to
+// schema errors when the position of the invalid node is known, or zero values if there isn't one
+func findErrorPosition(err string) (string, int, int) {
+	matches := errorPositionPattern.FindStringSubmatch(err)
+	if matches == nil {
+		return "", 0, 0
+	}
+	var line, col int
+	fmt.Sscan(matches[2], &line)
+	if matches[3] != "" {
+		fmt.Sscan(matches[3], &col)
+	}
+	return matches[1], line, col
+}
+
+func escapeAnnotationData(s string) string {
+	s = strings.ReplaceAll(s, "%", "%25")
+	s = strings.ReplaceAll(s, "\r", "%0D")
+	return strings.ReplaceAll(s, "\n", "%0A")
+}
+
+func escapeAnnotationProperty(s string) string {
+	s = escapeAnnotationData(s)
+	s = strings.ReplaceAll(s, ":", "%3A")
+	return strings.ReplaceAll(s, ",", "%2C")
+}
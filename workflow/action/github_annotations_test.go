@@ -0,0 +1,71 @@
+package action
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnnotationString(t *testing.T) {
+	annotation := &Annotation{
+		Level:   "error",
+		File:    "path/to/file,with:chars",
+		Line:    3,
+		Col:     5,
+		Title:   "gflows: error",
+		Message: "100% wrong\nsecond line",
+	}
+
+	assert.Equal(t, "::error file=path/to/file%2Cwith%3Achars,line=3,col=5,title=gflows%3A error::100%25 wrong%0Asecond line", annotation.String())
+}
+
+func TestGetAnnotations(t *testing.T) {
+	report := &WorkflowReport{
+		Name:           "test",
+		Source:         ".gflows/workflows/test.jsonnet",
+		Destination:    ".github/workflows/test.yml",
+		TemplateErrors: []string{"RUNTIME ERROR: foo\n\t.gflows/workflows/test.jsonnet:12:3-8\tobject <anonymous>"},
+		SchemaErrors:   []string{"(root): jobs is required"},
+		Content:        &ContentCheckReport{Valid: false, Errors: []string{"Content is out of date"}},
+		Warnings:       []string{"Schema checks disabled for test, skipping"},
+	}
+
+	annotations := report.GetAnnotations()
+
+	assert.Equal(t, []*Annotation{
+		{Level: "error", File: ".gflows/workflows/test.jsonnet", Line: 12, Col: 3, Title: "gflows: template error", Message: report.TemplateErrors[0]},
+		{Level: "error", File: ".gflows/workflows/test.jsonnet", Title: "gflows: invalid schema", Message: "(root): jobs is required"},
+		{Level: "error", File: ".github/workflows/test.yml", Title: "gflows: out of date", Message: "Content is out of date. Run \"gflows update\" to update."},
+		{Level: "warning", File: ".gflows/workflows/test.jsonnet", Title: "gflows", Message: "Schema checks disabled for test, skipping"},
+	}, annotations)
+}
+
+func TestGetAnnotationsForLocatedSchemaErrors(t *testing.T) {
+	report := &WorkflowReport{
+		Name:         "test",
+		Source:       ".gflows/workflows/test",
+		SchemaErrors: []string{".gflows/workflows/test/config.yml:5: jobs.hello: runs-on is required"},
+	}
+
+	annotations := report.GetAnnotations()
+
+	assert.Equal(t, []*Annotation{
+		{Level: "error", File: ".gflows/workflows/test/config.yml", Line: 5, Title: "gflows: invalid schema", Message: report.SchemaErrors[0]},
+	}, annotations)
+}
+
+func TestGetAnnotationsForHandEditedWorkflow(t *testing.T) {
+	report := &WorkflowReport{
+		Name:        "test",
+		Description: ".gflows/workflows/test.jsonnet",
+		Destination: ".github/workflows/test.yml",
+		Status:      StatusHandEdited,
+		Content:     &ContentCheckReport{Valid: false, Errors: []string{"Workflow \"test\" has been edited since it was generated"}},
+	}
+
+	annotations := report.GetAnnotations()
+
+	assert.Equal(t, []*Annotation{
+		{Level: "error", File: ".github/workflows/test.yml", Title: "gflows: edited by hand", Message: "Workflow \"test\" has been edited since it was generated. Make the changes in the template (.gflows/workflows/test.jsonnet) instead."},
+	}, annotations)
+}
@@ -2,7 +2,10 @@ package action
 
 func (manager *WorkflowManager) ImportWorkflows() error {
 	imported := 0
-	workflows := manager.GetWorkflows()
+	workflows, err := manager.GetWorkflows()
+	if err != nil {
+		return err
+	}
 	for _, workflow := range workflows {
 		manager.logger.Println("Found workflow:", workflow.Path)
 		if workflow.Definition == nil {
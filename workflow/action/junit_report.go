@@ -0,0 +1,93 @@
+package action
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string         `xml:"name,attr"`
+	ClassName string         `xml:"classname,attr"`
+	File      string         `xml:"file,attr"`
+	Failures  []junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Type    string `xml:"type,attr"`
+	Message string `xml:"message,attr"`
+	Details string `xml:",chardata"`
+}
+
+// FormatJUnitReport - returns a JUnit XML report with a test case for each workflow, and a failure
+// for each failed template, schema or content check
+func FormatJUnitReport(reports []*WorkflowReport) (string, error) {
+	suite := junitTestSuite{
+		Name:      "gflows",
+		TestCases: []junitTestCase{},
+	}
+	for _, report := range reports {
+		testCase := junitTestCase{
+			Name:      report.Name,
+			ClassName: "gflows.workflows",
+			File:      report.Source,
+		}
+		for _, err := range report.TemplateErrors {
+			testCase.Failures = append(testCase.Failures, newJUnitFailure("template", err))
+		}
+		for _, err := range report.SchemaErrors {
+			testCase.Failures = append(testCase.Failures, newJUnitFailure("schema", err))
+		}
+		if report.Content != nil {
+			failureType := "content"
+			switch report.Status {
+			case StatusOrphaned:
+				failureType = "orphaned"
+			case StatusHandEdited:
+				failureType = "hand_edited"
+			}
+			for _, err := range report.Content.Errors {
+				testCase.Failures = append(testCase.Failures, newJUnitFailure(failureType, err))
+			}
+		}
+		suite.Tests++
+		if len(testCase.Failures) > 0 {
+			suite.Failures++
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	suites := junitTestSuites{
+		Name:     "gflows",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Suites:   []junitTestSuite{suite},
+	}
+	out, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(out) + "\n", nil
+}
+
+func newJUnitFailure(failureType string, err string) junitFailure {
+	return junitFailure{
+		Type:    failureType,
+		Message: strings.Split(err, "\n")[0],
+		Details: err,
+	}
+}
@@ -0,0 +1,39 @@
+package action
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatJUnitReport(t *testing.T) {
+	reports := []*WorkflowReport{
+		{
+			Name:   "valid",
+			Source: ".gflows/workflows/valid.jsonnet",
+			Status: StatusUpToDate,
+		},
+		{
+			Name:         "invalid",
+			Source:       ".gflows/workflows/invalid.jsonnet",
+			Status:       StatusInvalidSchema,
+			SchemaErrors: []string{"(root): jobs is required"},
+			Content:      &ContentCheckReport{Valid: false, Errors: []string{"Content is out of date"}},
+		},
+	}
+
+	out, err := FormatJUnitReport(reports)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="gflows" tests="2" failures="1">
+  <testsuite name="gflows" tests="2" failures="1">
+    <testcase name="valid" classname="gflows.workflows" file=".gflows/workflows/valid.jsonnet"></testcase>
+    <testcase name="invalid" classname="gflows.workflows" file=".gflows/workflows/invalid.jsonnet">
+      <failure type="schema" message="(root): jobs is required">(root): jobs is required</failure>
+      <failure type="content" message="Content is out of date">Content is out of date</failure>
+    </testcase>
+  </testsuite>
+</testsuites>
+`, out)
+}
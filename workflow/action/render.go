@@ -0,0 +1,86 @@
+package action
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jbrunton/gflows/workflow"
+)
+
+// RenderFormats - the valid options for the --output flag of the render command
+var RenderFormats = []string{"yaml", "json"}
+
+// RenderWorkflows - evaluates the templates for the named workflows and returns the generated
+// content (as YAML documents, or as JSON values if the format is "json"), in the order given
+func (manager *WorkflowManager) RenderWorkflows(names []string, includeHeader bool, format string) (string, error) {
+	definitions, err := manager.getNamedDefinitions(names)
+	if err != nil {
+		return "", err
+	}
+
+	documents := []string{}
+	for _, definition := range definitions {
+		if !definition.Status.Valid {
+			return "", fmt.Errorf("Error rendering %s:\n%s", definition.Name, strings.Join(definition.Status.Errors, "\n"))
+		}
+
+		document, err := renderDefinition(definition, includeHeader, format)
+		if err != nil {
+			return "", err
+		}
+		documents = append(documents, document)
+	}
+
+	if format == "json" {
+		return strings.Join(documents, ""), nil
+	}
+	return strings.Join(documents, "---\n"), nil
+}
+
+// getNamedDefinitions - returns the definitions for the named workflows, in the order given, or an
+// error if any are unknown
+func (manager *WorkflowManager) getNamedDefinitions(names []string) ([]*workflow.Definition, error) {
+	filter, err := workflow.NewWorkflowFilter(names, nil)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := manager.GetWorkflowDefinitionsMatching(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	definitions := []*workflow.Definition{}
+	for _, name := range names {
+		var definition *workflow.Definition
+		for _, candidate := range candidates {
+			if candidate.Name == name {
+				definition = candidate
+				break
+			}
+		}
+		if definition == nil {
+			return nil, fmt.Errorf("Unknown workflow: %q", name)
+		}
+		definitions = append(definitions, definition)
+	}
+	return definitions, nil
+}
+
+func renderDefinition(definition *workflow.Definition, includeHeader bool, format string) (string, error) {
+	switch format {
+	case "json":
+		out, err := json.MarshalIndent(definition.JSON, "", "  ")
+		return string(out) + "\n", err
+	case "yaml":
+		content := definition.Content
+		if !includeHeader {
+			content = definition.Body()
+		}
+		if !strings.HasSuffix(content, "\n") {
+			content = content + "\n"
+		}
+		return content, nil
+	}
+	return "", fmt.Errorf("Unexpected format: %q", format)
+}
@@ -0,0 +1,152 @@
+package action
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jbrunton/gflows/config"
+	"github.com/jbrunton/gflows/io"
+	"github.com/jbrunton/gflows/io/content"
+	"github.com/jbrunton/gflows/io/diff"
+	"github.com/jbrunton/gflows/workflow"
+	"github.com/spf13/afero"
+
+	fdiff "github.com/go-git/go-git/v5/plumbing/format/diff"
+)
+
+// WorkflowChangeType - how a generated workflow differs between two revisions
+type WorkflowChangeType string
+
+const (
+	WorkflowAdded     WorkflowChangeType = "added"
+	WorkflowRemoved   WorkflowChangeType = "removed"
+	WorkflowChanged   WorkflowChangeType = "changed"
+	WorkflowUnchanged WorkflowChangeType = "unchanged"
+	WorkflowInvalid   WorkflowChangeType = "invalid"
+)
+
+// WorkflowChange - the difference between the definitions of a workflow at two revisions. Base
+// (or Head) is nil if the workflow is added (or removed).
+type WorkflowChange struct {
+	Name string
+	Type WorkflowChangeType
+	Base *workflow.Definition
+	Head *workflow.Definition
+}
+
+// CompareWorkflowDefinitions - compares the workflows generated at two revisions, returning the
+// changes ordered by name. Workflows which can't be generated at the head revision are invalid.
+func CompareWorkflowDefinitions(base []*workflow.Definition, head []*workflow.Definition) []*WorkflowChange {
+	changesByName := make(map[string]*WorkflowChange)
+	for _, definition := range base {
+		changesByName[definition.Name] = &WorkflowChange{Name: definition.Name, Type: WorkflowRemoved, Base: definition}
+	}
+	for _, definition := range head {
+		change := changesByName[definition.Name]
+		if change == nil {
+			change = &WorkflowChange{Name: definition.Name, Type: WorkflowAdded}
+			changesByName[definition.Name] = change
+		}
+		change.Head = definition
+		switch {
+		case !definition.Status.Valid:
+			change.Type = WorkflowInvalid
+		case change.Base == nil:
+			change.Type = WorkflowAdded
+		case change.Base.Status.Valid && change.Base.Content == definition.Content:
+			change.Type = WorkflowUnchanged
+		default:
+			change.Type = WorkflowChanged
+		}
+	}
+
+	changes := []*WorkflowChange{}
+	for _, change := range changesByName {
+		changes = append(changes, change)
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Name < changes[j].Name
+	})
+	return changes
+}
+
+// FormatWorkflowChangesPatch - returns a unified diff of the generated workflows for the given
+// changes. Invalid workflows are omitted.
+func FormatWorkflowChangesPatch(changes []*WorkflowChange) string {
+	filePatches := []fdiff.FilePatch{}
+	for _, change := range changes {
+		if change.Type == WorkflowUnchanged || change.Type == WorkflowInvalid {
+			continue
+		}
+		var from, to *string
+		var path string
+		if change.Base != nil && change.Base.Status.Valid {
+			from = &change.Base.Content
+			path = change.Base.Destination
+		}
+		if change.Head != nil {
+			to = &change.Head.Content
+			path = change.Head.Destination
+		}
+		if from != nil && to != nil && change.Base.Destination != change.Head.Destination {
+			// the workflow moved, so show it as a deletion and a creation
+			filePatches = append(filePatches, diff.CreateFileChangePatch(change.Base.Destination, from, nil))
+			from = nil
+		}
+		filePatches = append(filePatches, diff.CreateFileChangePatch(path, from, to))
+	}
+	return diff.NewPatch(filePatches, "").Format()
+}
+
+// FormatWorkflowChangesSummary - returns a summary of the changes, listing added, removed, changed
+// and invalid workflows
+func FormatWorkflowChangesSummary(changes []*WorkflowChange) string {
+	namesByType := make(map[WorkflowChangeType][]string)
+	for _, change := range changes {
+		namesByType[change.Type] = append(namesByType[change.Type], change.Name)
+	}
+
+	lines := []string{fmt.Sprintf("%d added, %d removed, %d changed, %d unchanged",
+		len(namesByType[WorkflowAdded]),
+		len(namesByType[WorkflowRemoved]),
+		len(namesByType[WorkflowChanged]),
+		len(namesByType[WorkflowUnchanged]))}
+	for _, changeType := range []WorkflowChangeType{WorkflowAdded, WorkflowRemoved, WorkflowChanged} {
+		if names := namesByType[changeType]; len(names) > 0 {
+			lines = append(lines, fmt.Sprintf("  %s: %s", changeType, strings.Join(names, ", ")))
+		}
+	}
+	for _, change := range changes {
+		if change.Type == WorkflowInvalid {
+			lines = append(lines, fmt.Sprintf("  invalid: %s", change.Name))
+			for _, err := range change.Head.Status.Errors {
+				lines = append(lines, fmt.Sprintf("    ► %s", strings.Split(err, "\n")[0]))
+			}
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// GetRevisionDefinitions - returns the workflow definitions for the context as it was at the given
+// revision. The repository is read from git into memory and mounted in place of the working copy,
+// so the working directory is left untouched while packages outside the repository (and the
+// package caches) are still read from disk.
+func GetRevisionDefinitions(container *Container, revision string, opts config.ContextOpts) ([]*workflow.Definition, error) {
+	tree := io.CreateMemFs()
+	repoDir, err := container.GitAdapter().ReadTree(revision, tree)
+	if err != nil {
+		return nil, err
+	}
+	fs := &afero.Afero{Fs: io.NewMountFs(container.FileSystem().Fs, repoDir, tree.Fs)}
+
+	context, err := config.NewContext(fs, container.Logger(), opts)
+	if err != nil {
+		return nil, err
+	}
+	ioContainer := io.NewContainer(fs, container.Logger(), container.Styles(), container.GitAdapter())
+	revisionContainer := NewContainer(content.NewContainer(ioContainer, container.HttpClient()), context)
+	defer revisionContainer.Environment().CleanUp()
+
+	return revisionContainer.WorkflowManager().GetWorkflowDefinitions()
+}
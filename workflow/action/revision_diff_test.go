@@ -0,0 +1,120 @@
+package action
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jbrunton/gflows/config"
+	"github.com/jbrunton/gflows/fixtures"
+	"github.com/jbrunton/gflows/io/content"
+	"github.com/jbrunton/gflows/workflow"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRevisionDefinition(name string, content string) *workflow.Definition {
+	return &workflow.Definition{
+		Name:        name,
+		Destination: ".github/workflows/" + name + ".yml",
+		Content:     content,
+		Status:      workflow.ValidationResult{Valid: true},
+	}
+}
+
+func TestCompareWorkflowDefinitions(t *testing.T) {
+	invalid := newRevisionDefinition("invalid", "")
+	invalid.Status = workflow.ValidationResult{Valid: false, Errors: []string{"syntax error\ndetails"}}
+	base := []*workflow.Definition{
+		newRevisionDefinition("changed", "a: 1\n"),
+		newRevisionDefinition("removed", "a: 1\n"),
+		newRevisionDefinition("unchanged", "a: 1\n"),
+		newRevisionDefinition("invalid", "a: 1\n"),
+	}
+	head := []*workflow.Definition{
+		newRevisionDefinition("unchanged", "a: 1\n"),
+		newRevisionDefinition("changed", "a: 2\n"),
+		newRevisionDefinition("added", "a: 1\n"),
+		invalid,
+	}
+
+	changes := CompareWorkflowDefinitions(base, head)
+
+	types := make(map[string]WorkflowChangeType)
+	names := []string{}
+	for _, change := range changes {
+		types[change.Name] = change.Type
+		names = append(names, change.Name)
+	}
+	assert.Equal(t, []string{"added", "changed", "invalid", "removed", "unchanged"}, names)
+	assert.Equal(t, map[string]WorkflowChangeType{
+		"added":     WorkflowAdded,
+		"changed":   WorkflowChanged,
+		"invalid":   WorkflowInvalid,
+		"removed":   WorkflowRemoved,
+		"unchanged": WorkflowUnchanged,
+	}, types)
+
+	assert.Equal(t, `1 added, 1 removed, 1 changed, 1 unchanged
+  added: added
+  removed: removed
+  changed: changed
+  invalid: invalid
+    ► syntax error
+`, FormatWorkflowChangesSummary(changes))
+}
+
+func TestFormatWorkflowChangesPatch(t *testing.T) {
+	base := []*workflow.Definition{
+		newRevisionDefinition("changed", "a: 1\n"),
+		newRevisionDefinition("unchanged", "a: 1\n"),
+	}
+	head := []*workflow.Definition{
+		newRevisionDefinition("changed", "a: 2\n"),
+		newRevisionDefinition("unchanged", "a: 1\n"),
+	}
+
+	patch := FormatWorkflowChangesPatch(CompareWorkflowDefinitions(base, head))
+
+	assert.Contains(t, patch, "--- a/.github/workflows/changed.yml\n+++ b/.github/workflows/changed.yml\n")
+	assert.Contains(t, patch, "-a: 1\n+a: 2\n")
+	assert.NotContains(t, patch, "unchanged.yml")
+}
+
+func TestGetRevisionDefinitionsWithPackages(t *testing.T) {
+	ioContainer, context, _ := fixtures.NewTestContext("")
+	httpClient := &http.Client{Transport: fixtures.NewMockRoundTripper()}
+	container := NewContainer(content.NewContainer(ioContainer, httpClient), context)
+	fs := container.FileSystem()
+	// a local package outside the repository, which should be read from disk
+	fs.WriteFile("/path/to/local-lib/gflowspkg.json", []byte(`{"files": ["libs/git.libsonnet"]}`), 0644)
+	fs.WriteFile("/path/to/local-lib/libs/git.libsonnet", []byte(`{ checkout: { uses: "actions/checkout@v2" } }`), 0644)
+	gitAdapter := container.GitAdapter().(*fixtures.TestGitAdapter)
+	gitAdapter.StubRepo("git@example.com:my/repo.git", &map[string]string{
+		"my-lib/gflowspkg.json":       `{"files": ["libs/steps.libsonnet"]}`,
+		"my-lib/libs/steps.libsonnet": `{ run(command): { run: command } }`,
+	})
+	gitAdapter.StubRevision("HEAD~1", &map[string]string{
+		".gflows/config.yml": strings.Join([]string{
+			"templates:",
+			"  engine: jsonnet",
+			"  defaults:",
+			"    dependencies: [git@example.com:my/repo.git/my-lib, /path/to/local-lib]",
+		}, "\n"),
+		".gflows/workflows/test.jsonnet": strings.Join([]string{
+			`local git = import "git.libsonnet";`,
+			`local steps = import "steps.libsonnet";`,
+			`std.manifestYamlDoc({ "on": "push", jobs: { test: { "runs-on": "ubuntu-latest", steps: [git.checkout, steps.run("make test")] } } }, quote_keys=false)`,
+		}, "\n"),
+	})
+
+	definitions, err := GetRevisionDefinitions(container, "HEAD~1", config.ContextOpts{ConfigPath: ".gflows/config.yml"})
+
+	assert.NoError(t, err)
+	assert.Len(t, definitions, 1)
+	assert.Equal(t, "test", definitions[0].Name)
+	assert.Contains(t, definitions[0].Content, "uses: \"actions/checkout@v2\"")
+	assert.Contains(t, definitions[0].Content, "run: \"make test\"")
+	// the working copy is left as it was
+	content, _ := fs.ReadFile(".gflows/config.yml")
+	assert.Equal(t, "templates:\n  engine: ytt", string(content))
+}
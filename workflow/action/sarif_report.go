@@ -0,0 +1,123 @@
+package action
+
+import (
+	"encoding/json"
+)
+
+const (
+	sarifSchemaURI       = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifTemplateErrorID = "template-error"
+	sarifSchemaErrorID   = "schema-error"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// FormatSarifReport - returns a SARIF 2.1 report with a result for each template or schema error
+func FormatSarifReport(reports []*WorkflowReport, version string) (string, error) {
+	results := []sarifResult{}
+	for _, report := range reports {
+		for _, err := range report.TemplateErrors {
+			line, col := findSourcePosition(report.Source, err)
+			results = append(results, newSarifResult(sarifTemplateErrorID, report.Source, line, col, err))
+		}
+		for _, err := range report.SchemaErrors {
+			file, line, col := findErrorPosition(err)
+			if file == "" {
+				file = report.Source
+			}
+			results = append(results, newSarifResult(sarifSchemaErrorID, file, line, col, err))
+		}
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchemaURI,
+		Version: "2.1.0",
+		Runs: []sarifRun{
+			{
+				Tool: sarifTool{
+					Driver: sarifDriver{
+						Name:           "gflows",
+						Version:        version,
+						InformationURI: "https://github.com/jbrunton/gflows",
+						Rules: []sarifRule{
+							{ID: sarifTemplateErrorID, ShortDescription: sarifMessage{Text: "Workflow template failed to evaluate"}},
+							{ID: sarifSchemaErrorID, ShortDescription: sarifMessage{Text: "Generated workflow does not match the workflow schema"}},
+						},
+					},
+				},
+				Results: results,
+			},
+		},
+	}
+	out, err := json.MarshalIndent(log, "", "  ")
+	return string(out) + "\n", err
+}
+
+func newSarifResult(ruleID string, file string, line int, col int, message string) sarifResult {
+	location := sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{URI: file},
+	}
+	if line > 0 {
+		location.Region = &sarifRegion{StartLine: line, StartColumn: col}
+	}
+	return sarifResult{
+		RuleID:    ruleID,
+		Level:     "error",
+		Message:   sarifMessage{Text: message},
+		Locations: []sarifLocation{{PhysicalLocation: location}},
+	}
+}
@@ -0,0 +1,47 @@
+package action
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatSarifReport(t *testing.T) {
+	reports := []*WorkflowReport{
+		{
+			Name:           "test",
+			Source:         ".gflows/workflows/test.jsonnet",
+			TemplateErrors: []string{".gflows/workflows/test.jsonnet:2:9-10 Unexpected: \"}\""},
+			SchemaErrors:   []string{"(root): jobs is required"},
+			Content:        &ContentCheckReport{Valid: false, Errors: []string{"Content is out of date"}},
+		},
+	}
+
+	out, err := FormatSarifReport(reports, "1.2.3")
+	assert.NoError(t, err)
+
+	var log sarifLog
+	assert.NoError(t, json.Unmarshal([]byte(out), &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	assert.Equal(t, "1.2.3", log.Runs[0].Tool.Driver.Version)
+	assert.Equal(t, []sarifResult{
+		{
+			RuleID:  "template-error",
+			Level:   "error",
+			Message: sarifMessage{Text: reports[0].TemplateErrors[0]},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: ".gflows/workflows/test.jsonnet"},
+				Region:           &sarifRegion{StartLine: 2, StartColumn: 9},
+			}}},
+		},
+		{
+			RuleID:  "schema-error",
+			Level:   "error",
+			Message: sarifMessage{Text: "(root): jobs is required"},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: ".gflows/workflows/test.jsonnet"},
+			}}},
+		},
+	}, log.Runs[0].Results)
+}
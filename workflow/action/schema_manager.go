@@ -0,0 +1,37 @@
+package action
+
+import (
+	"fmt"
+
+	"github.com/jbrunton/gflows/config"
+	"github.com/jbrunton/gflows/io/content"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+type SchemaManager struct {
+	context       *config.GFlowsContext
+	contentReader *content.Reader
+	contentWriter *content.Writer
+}
+
+func NewSchemaManager(context *config.GFlowsContext, contentReader *content.Reader, contentWriter *content.Writer) *SchemaManager {
+	return &SchemaManager{
+		context:       context,
+		contentReader: contentReader,
+		contentWriter: contentWriter,
+	}
+}
+
+// UpdateSchema - downloads the schema at the given URI and saves it as the cached copy of the
+// GitHub workflow schema for the context
+func (manager *SchemaManager) UpdateSchema(uri string) error {
+	source, err := manager.contentReader.ReadContent(uri)
+	if err != nil {
+		return err
+	}
+	_, err = gojsonschema.NewSchema(gojsonschema.NewStringLoader(source))
+	if err != nil {
+		return fmt.Errorf("Invalid schema at %s: %s", uri, err)
+	}
+	return manager.contentWriter.UpdateFileContent(manager.context.SchemaCachePath(), source, fmt.Sprintf("(from %s)", uri))
+}
@@ -0,0 +1,39 @@
+package action
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/jbrunton/gflows/fixtures"
+	"github.com/jbrunton/gflows/io/content"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSchemaManager() (*content.Container, *fixtures.MockRoundTripper, *SchemaManager) {
+	ioContainer, context, _ := fixtures.NewTestContext("")
+	roundTripper := fixtures.NewMockRoundTripper()
+	container := content.NewContainer(ioContainer, &http.Client{Transport: roundTripper})
+	return container, roundTripper, NewSchemaManager(context, container.ContentReader(), container.ContentWriter())
+}
+
+func TestUpdateSchema(t *testing.T) {
+	container, roundTripper, schemaManager := newTestSchemaManager()
+	roundTripper.StubBody("https://example.com/schema.json", `{"required": ["jobs"]}`)
+
+	err := schemaManager.UpdateSchema("https://example.com/schema.json")
+
+	assert.NoError(t, err)
+	cachedSchema, _ := container.FileSystem().ReadFile(".gflows/schemas/github-workflow.json")
+	assert.Equal(t, `{"required": ["jobs"]}`, string(cachedSchema))
+}
+
+func TestUpdateSchemaInvalidSchema(t *testing.T) {
+	container, roundTripper, schemaManager := newTestSchemaManager()
+	roundTripper.StubBody("https://example.com/schema.json", `not a schema`)
+
+	err := schemaManager.UpdateSchema("https://example.com/schema.json")
+
+	assert.Regexp(t, "^Invalid schema at https://example.com/schema.json", err)
+	exists, _ := container.FileSystem().Exists(".gflows/schemas/github-workflow.json")
+	assert.False(t, exists, "expected invalid schema not to be cached")
+}
@@ -1,91 +1,348 @@
 package action
 
 import (
-	"fmt"
-	"log"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/inancgumus/screen"
 	"github.com/jbrunton/gflows/config"
+	"github.com/jbrunton/gflows/io/pkg"
+	"github.com/jbrunton/gflows/workflow"
 )
 
+// DefaultDebounce - how long the watcher waits for a burst of events to settle before invoking
+// onChange
+const DefaultDebounce = 100 * time.Millisecond
+
+// fsWatcher - the subset of fsnotify.Watcher used to register directories, so that tests can
+// substitute it
+type fsWatcher interface {
+	Add(name string) error
+	Remove(name string) error
+}
+
 type Watcher struct {
 	manager *WorkflowManager
 	context *config.GFlowsContext
+
+	// Debounce - how long to wait after the last relevant event before invoking onChange
+	Debounce time.Duration
+
+	// snapshots - the content of each watched workflow after the last call to onChange, used to
+	// ignore events caused by onChange writing to the workflows (e.g. in watch --update)
+	snapshots map[string]string
+
+	// definitions - the definitions returned by the last call to onChange, whose dependencies are
+	// watched. Reused by refresh, so that templates are only evaluated by onChange.
+	definitions []*workflow.Definition
+
+	// sources - the observable sources and generated workflows, as of the last refresh
+	sources map[string]bool
+
+	// dirs - the directories currently registered with fsnotify
+	dirs map[string]bool
+
+	clearScreen func()
 }
 
 func NewWatcher(manager *WorkflowManager, context *config.GFlowsContext) *Watcher {
 	return &Watcher{
-		manager: manager,
-		context: context,
+		manager:   manager,
+		context:   context,
+		Debounce:  DefaultDebounce,
+		snapshots: make(map[string]string),
+		sources:   make(map[string]bool),
+		dirs:      make(map[string]bool),
+		clearScreen: func() {
+			screen.Clear()
+			screen.MoveTopLeft()
+		},
 	}
 }
 
 func (watcher *Watcher) getWatchFiles() ([]string, error) {
-	files, err := watcher.manager.GetObservableSources()
+	files, err := watcher.manager.GetObservableSources(watcher.definitions)
 	if err != nil {
 		return nil, err
 	}
-	for _, workflow := range watcher.manager.GetWorkflows() {
-		files = append(files, workflow.Path)
+	workflowPaths, err := watcher.manager.getWorkflowPaths()
+	if err != nil {
+		return nil, err
 	}
-	return files, nil
+	return append(files, workflowPaths...), nil
 }
 
-// WatchWorkflows - watch workflow files and invoke onChange on any changes
-func (watcher *Watcher) WatchWorkflows(onChange func()) {
-	log.Println("Watching workflows")
+func (watcher *Watcher) githubWorkflowsDir() string {
+	return filepath.Join(watcher.manager.context.GitHubDir, "workflows")
+}
 
-	fswatcher, err := fsnotify.NewWatcher()
+// isGitHubPath - returns true if the path is in the GitHub directory
+func (watcher *Watcher) isGitHubPath(path string) bool {
+	gitHubDir := filepath.Clean(watcher.manager.context.GitHubDir)
+	return path == gitHubDir || strings.HasPrefix(path, gitHubDir+string(filepath.Separator))
+}
+
+// isWorkflowPath - returns true if the path is a workflow in the GitHub workflows directory
+func (watcher *Watcher) isWorkflowPath(path string) bool {
+	return filepath.Dir(path) == filepath.Clean(watcher.githubWorkflowsDir()) && workflow.IsWorkflowFile(path)
+}
+
+// getWatchDirs - returns the directories to register with fsnotify: every directory under the
+// local libs, the templates directory and the GitHub workflows directory. Watching directories
+// rather than files means new, removed and renamed files are observed too. If a path doesn't
+// exist yet then its nearest existing ancestor is watched so that its creation is observed.
+func (watcher *Watcher) getWatchDirs() (map[string]bool, error) {
+	context := watcher.manager.context
+	fs := watcher.manager.fs
+	dirs := make(map[string]bool)
+	roots := append(context.Config.GetAllLibs(), context.WorkflowsDir(), context.LibsDir(), watcher.githubWorkflowsDir())
+	for _, root := range roots {
+		libInfo, err := pkg.GetLibInfo(root, fs)
+		if err != nil {
+			return nil, err
+		}
+
+		if libInfo.IsRemote {
+			continue
+		}
+
+		if !libInfo.Exists {
+			dir, err := watcher.nearestExistingDir(root)
+			if err != nil {
+				return nil, err
+			}
+			dirs[dir] = true
+			continue
+		}
+
+		if !libInfo.IsDir {
+			dirs[filepath.Dir(root)] = true
+			continue
+		}
+
+		err = fs.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				dirs[filepath.Clean(path)] = true
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return dirs, nil
+}
+
+func (watcher *Watcher) nearestExistingDir(path string) (string, error) {
+	dir := filepath.Dir(filepath.Clean(path))
+	for {
+		exists, err := watcher.manager.fs.DirExists(dir)
+		if err != nil {
+			return "", err
+		}
+		parent := filepath.Dir(dir)
+		if exists || parent == dir {
+			return dir, nil
+		}
+		dir = parent
+	}
+}
+
+// refresh - re-scans the observable sources (i.e. the templates, and the files they imported when
+// onChange last evaluated them) and registers (or unregisters) directories with the fswatcher to
+// match
+func (watcher *Watcher) refresh(fswatcher fsWatcher) error {
+	files, err := watcher.getWatchFiles()
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+	dirs, err := watcher.getWatchDirs()
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		// templates may import files from outside the lib directories. The dependencies are those
+		// from the last onChange, so they may since have been removed.
+		dir, err := watcher.nearestExistingDir(file)
+		if err != nil {
+			return err
+		}
+		dirs[dir] = true
 	}
-	defer fswatcher.Close()
 
-	done := make(chan bool)
-	go func() {
-		for {
-			select {
-			case event, ok := <-fswatcher.Events:
-				if !ok {
-					return
-				}
-				if event.Op&fsnotify.Write == fsnotify.Write {
-					screen.Clear()
-					screen.MoveTopLeft()
-					log.Println("modified file:", event.Name)
-					onChange()
-				}
-			case err, ok := <-fswatcher.Errors:
-				if !ok {
-					return
-				}
-				log.Println("error:", err)
+	for dir := range dirs {
+		if !watcher.dirs[dir] {
+			err = fswatcher.Add(dir)
+			if err != nil {
+				return err
 			}
+			watcher.dirs[dir] = true
+		}
+	}
+	for dir := range watcher.dirs {
+		if !dirs[dir] {
+			// fsnotify removes watches on deleted directories itself, so ignore errors here
+			fswatcher.Remove(dir)
+			delete(watcher.dirs, dir)
 		}
-	}()
+	}
 
-	screen.Clear()
-	screen.MoveTopLeft()
-	log.Println("Watching workflow templates")
+	watcher.sources = make(map[string]bool)
+	for _, file := range files {
+		watcher.sources[filepath.Clean(file)] = true
+	}
+	return nil
+}
 
-	sources, err := watcher.getWatchFiles()
-	if err != nil {
-		log.Fatal(err)
-		os.Exit(1)
+// handleEvent - updates the watched directories after structural changes (new, removed or renamed
+// files) and returns true if the event should trigger onChange
+func (watcher *Watcher) handleEvent(fswatcher fsWatcher, event fsnotify.Event) bool {
+	path := filepath.Clean(event.Name)
+	if strings.HasPrefix(filepath.Base(path), ".") {
+		// e.g. the temp files written by SafelyWriteFile, or editor swap files
+		return false
+	}
+	if watcher.isGitHubPath(path) {
+		// generated workflows can't change the sources, so there's no need to refresh
+		if event.Op&(fsnotify.Create|fsnotify.Remove|fsnotify.Rename|fsnotify.Write) == 0 {
+			return false
+		}
+		relevant := watcher.sources[path] || watcher.isWorkflowPath(path)
+		return relevant && !watcher.isUnchanged(path)
 	}
 
-	for _, source := range sources {
-		fmt.Println("  Watching", source)
-		err = fswatcher.Add(source)
+	relevant := watcher.sources[path] || watcher.dirs[path]
+	if event.Op&(fsnotify.Create|fsnotify.Remove|fsnotify.Rename) != 0 {
+		err := watcher.refresh(fswatcher)
 		if err != nil {
-			log.Fatal(err)
-			os.Exit(1)
+			watcher.manager.logger.Println("error:", err)
 		}
+		relevant = relevant || watcher.sources[path] || watcher.dirs[path]
+	} else if event.Op&fsnotify.Write == 0 {
+		// e.g. chmod
+		return false
 	}
 
-	onChange()
+	if relevant && watcher.isUnchanged(path) {
+		return false
+	}
+	return relevant
+}
+
+// takeSnapshots - records the current content of the generated workflows
+func (watcher *Watcher) takeSnapshots() {
+	watcher.snapshots = make(map[string]string)
+	paths, err := watcher.manager.getWorkflowPaths()
+	if err != nil {
+		// without snapshots, changes to workflows are observed rather than ignored
+		return
+	}
+	for _, path := range paths {
+		content, err := watcher.manager.readWorkflow(path)
+		if err == nil && content != nil {
+			watcher.snapshots[path] = *content
+		}
+	}
+}
+
+// isUnchanged - returns true if the file is a workflow whose content is the same as when the
+// snapshots were taken, e.g. because the event was caused by onChange writing it
+func (watcher *Watcher) isUnchanged(path string) bool {
+	snapshot, ok := watcher.snapshots[path]
+	if !ok {
+		return false
+	}
+	exists, err := watcher.manager.fs.Exists(path)
+	if err != nil || !exists {
+		return false
+	}
+	content, err := watcher.manager.readWorkflow(path)
+	return err == nil && content != nil && *content == snapshot
+}
+
+func (watcher *Watcher) runOnChange(onChange OnChangeFunc) {
+	watcher.definitions = onChange()
+	watcher.takeSnapshots()
+}
+
+// watch - the event loop. Relevant events are debounced so that a burst of events (e.g. from an
+// editor's atomic save, or a checkout) invokes onChange once. Returns when either channel is
+// closed.
+func (watcher *Watcher) watch(fswatcher fsWatcher, events <-chan fsnotify.Event, errors <-chan error, onChange OnChangeFunc) {
+	var timer <-chan time.Time
+	changed := make(map[string]bool)
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if watcher.handleEvent(fswatcher, event) {
+				changed[filepath.Clean(event.Name)] = true
+				timer = time.After(watcher.Debounce)
+			}
+		case err, ok := <-errors:
+			if !ok {
+				return
+			}
+			watcher.manager.logger.Println("error:", err)
+		case <-timer:
+			timer = nil
+			watcher.clearScreen()
+			paths := []string{}
+			for path := range changed {
+				paths = append(paths, path)
+			}
+			sort.Strings(paths)
+			for _, path := range paths {
+				watcher.manager.logger.Println("changed:", path)
+			}
+			changed = make(map[string]bool)
+			watcher.runOnChange(onChange)
+			// the change may have added or removed imports
+			err := watcher.refresh(fswatcher)
+			if err != nil {
+				watcher.manager.logger.Println("error:", err)
+			}
+		}
+	}
+}
+
+// OnChangeFunc - invoked by the watcher when templates change. Returns the definitions it
+// evaluated (or nil if there are none), so that the watcher can observe their dependencies.
+type OnChangeFunc func() []*workflow.Definition
+
+// WatchWorkflows - watch workflow templates, libs and generated workflows and invoke onChange on
+// any changes. Changes to workflows made by onChange itself are ignored. Blocks until the
+// underlying watcher is closed, and returns an error if the watch couldn't be set up.
+func (watcher *Watcher) WatchWorkflows(onChange OnChangeFunc) error {
+	fswatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer fswatcher.Close()
+
+	// watch the templates before evaluating them, so that changes made meanwhile are observed
+	err = watcher.refresh(fswatcher)
+	if err != nil {
+		return err
+	}
+
+	watcher.clearScreen()
+	watcher.manager.logger.Println("Watching workflow templates")
+	watcher.runOnChange(onChange)
+	err = watcher.refresh(fswatcher)
+	if err != nil {
+		return err
+	}
 
-	<-done
+	watcher.watch(fswatcher, fswatcher.Events, fswatcher.Errors, onChange)
+	return nil
 }
@@ -0,0 +1,181 @@
+package action
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/jbrunton/gflows/fixtures"
+	"github.com/jbrunton/gflows/workflow"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatcherIgnoresOwnWrites(t *testing.T) {
+	fs, _, workflowManager := newTestWorkflowManager()
+	fs.WriteFile(".gflows/workflows/test.jsonnet", []byte(fixtures.ExampleJsonnetTemplate), 0644)
+	watcher := NewWatcher(workflowManager, nil)
+
+	watcher.runOnChange(func() []*workflow.Definition {
+		definitions, _ := workflowManager.UpdateWorkflows(nil, false)
+		return definitions
+	})
+
+	assert.True(t, watcher.isUnchanged(".github/workflows/test.yml"), "Expected writes by onChange to be ignored")
+	assert.False(t, watcher.isUnchanged(".gflows/workflows/test.jsonnet"), "Expected changes to templates to be observed")
+
+	fs.WriteFile(".github/workflows/test.yml", []byte("# edited by hand"), 0644)
+	assert.False(t, watcher.isUnchanged(".github/workflows/test.yml"), "Expected changes to workflows by others to be observed")
+}
+
+type stubFsWatcher struct {
+	dirs []string
+}
+
+func (watcher *stubFsWatcher) Add(name string) error {
+	watcher.dirs = append(watcher.dirs, name)
+	return nil
+}
+
+func (watcher *stubFsWatcher) Remove(name string) error {
+	for i, dir := range watcher.dirs {
+		if dir == name {
+			watcher.dirs = append(watcher.dirs[:i], watcher.dirs[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func newTestWatcher() (*afero.Afero, *Watcher, *stubFsWatcher) {
+	fs, _, workflowManager := newTestWorkflowManager()
+	fs.WriteFile(".gflows/workflows/test.jsonnet", []byte(fixtures.ExampleJsonnetTemplate), 0644)
+	fs.MkdirAll(".github/workflows", 0755)
+	watcher := NewWatcher(workflowManager, nil)
+	watcher.Debounce = 10 * time.Millisecond
+	watcher.clearScreen = func() {}
+	fswatcher := &stubFsWatcher{}
+	err := watcher.refresh(fswatcher)
+	if err != nil {
+		panic(err)
+	}
+	return fs, watcher, fswatcher
+}
+
+func TestWatcherWatchesDirectories(t *testing.T) {
+	fs, watcher, fswatcher := newTestWatcher()
+	assert.ElementsMatch(t, []string{".gflows", ".gflows/workflows", ".github/workflows"}, fswatcher.dirs)
+
+	// create a new lib directory, with a lib imported by the template
+	fs.WriteFile(".gflows/workflows/test.jsonnet", []byte("std.manifestYamlDoc(import 'nested/lib.libsonnet')"), 0644)
+	fs.WriteFile(".gflows/libs/nested/lib.libsonnet", []byte("{}"), 0644)
+	fs.WriteFile(".gflows/libs/nested/unused.libsonnet", []byte("{}"), 0644)
+	assert.True(t, watcher.handleEvent(fswatcher, fsnotify.Event{Name: ".gflows/libs", Op: fsnotify.Create}))
+	assert.ElementsMatch(t, []string{".gflows/workflows", ".gflows/libs", ".gflows/libs/nested", ".github/workflows"}, fswatcher.dirs)
+	// templates are only evaluated by onChange, so the import is observed once it runs
+	assert.False(t, watcher.sources[".gflows/libs/nested/lib.libsonnet"])
+	watcher.runOnChange(func() []*workflow.Definition {
+		definitions, _ := watcher.manager.GetWorkflowDefinitions()
+		return definitions
+	})
+	assert.NoError(t, watcher.refresh(fswatcher))
+	assert.True(t, watcher.sources[".gflows/libs/nested/lib.libsonnet"])
+	assert.False(t, watcher.handleEvent(fswatcher, fsnotify.Event{Name: ".gflows/libs/nested/unused.libsonnet", Op: fsnotify.Write}))
+
+	// remove it again
+	fs.RemoveAll(".gflows/libs")
+	assert.True(t, watcher.handleEvent(fswatcher, fsnotify.Event{Name: ".gflows/libs", Op: fsnotify.Remove}))
+	assert.ElementsMatch(t, []string{".gflows", ".gflows/workflows", ".github/workflows"}, fswatcher.dirs)
+	watcher.runOnChange(func() []*workflow.Definition {
+		definitions, _ := watcher.manager.GetWorkflowDefinitions()
+		return definitions
+	})
+	assert.NoError(t, watcher.refresh(fswatcher))
+	assert.False(t, watcher.sources[".gflows/libs/nested/lib.libsonnet"])
+}
+
+func TestWatcherHandlesStructuralChanges(t *testing.T) {
+	fs, watcher, fswatcher := newTestWatcher()
+
+	// new templates
+	fs.WriteFile(".gflows/workflows/new.jsonnet", []byte(fixtures.ExampleJsonnetTemplate), 0644)
+	assert.True(t, watcher.handleEvent(fswatcher, fsnotify.Event{Name: ".gflows/workflows/new.jsonnet", Op: fsnotify.Create}))
+	assert.True(t, watcher.sources[".gflows/workflows/new.jsonnet"])
+
+	// renamed templates
+	fs.Rename(".gflows/workflows/new.jsonnet", ".gflows/workflows/renamed.jsonnet")
+	assert.True(t, watcher.handleEvent(fswatcher, fsnotify.Event{Name: ".gflows/workflows/new.jsonnet", Op: fsnotify.Rename}))
+	assert.True(t, watcher.handleEvent(fswatcher, fsnotify.Event{Name: ".gflows/workflows/renamed.jsonnet", Op: fsnotify.Create}))
+	assert.False(t, watcher.sources[".gflows/workflows/new.jsonnet"])
+	assert.True(t, watcher.sources[".gflows/workflows/renamed.jsonnet"])
+
+	// deleted templates
+	fs.Remove(".gflows/workflows/renamed.jsonnet")
+	assert.True(t, watcher.handleEvent(fswatcher, fsnotify.Event{Name: ".gflows/workflows/renamed.jsonnet", Op: fsnotify.Remove}))
+	assert.False(t, watcher.sources[".gflows/workflows/renamed.jsonnet"])
+
+	// irrelevant files
+	fs.WriteFile(".gflows/workflows/notes.txt", []byte("notes"), 0644)
+	assert.False(t, watcher.handleEvent(fswatcher, fsnotify.Event{Name: ".gflows/workflows/notes.txt", Op: fsnotify.Create}))
+	assert.False(t, watcher.handleEvent(fswatcher, fsnotify.Event{Name: ".gflows/workflows/test.jsonnet", Op: fsnotify.Chmod}))
+}
+
+func TestWatcherIgnoresWorkflowEvents(t *testing.T) {
+	fs, watcher, fswatcher := newTestWatcher()
+	watcher.runOnChange(func() []*workflow.Definition {
+		definitions, _ := watcher.manager.UpdateWorkflows(nil, false)
+		return definitions
+	})
+	// not yet observed, so a refresh would add it to the sources
+	fs.WriteFile(".gflows/workflows/new.jsonnet", []byte(fixtures.ExampleJsonnetTemplate), 0644)
+
+	// temp files and generated workflows don't trigger a refresh
+	assert.False(t, watcher.handleEvent(fswatcher, fsnotify.Event{Name: ".github/workflows/.test.yml.tmp123", Op: fsnotify.Create}))
+	assert.False(t, watcher.handleEvent(fswatcher, fsnotify.Event{Name: ".github/workflows/.test.yml.tmp123", Op: fsnotify.Rename}))
+	assert.False(t, watcher.handleEvent(fswatcher, fsnotify.Event{Name: ".github/workflows/test.yml", Op: fsnotify.Create}))
+	assert.False(t, watcher.handleEvent(fswatcher, fsnotify.Event{Name: ".gflows/workflows/.new.jsonnet.swp", Op: fsnotify.Create}))
+	assert.False(t, watcher.sources[".gflows/workflows/new.jsonnet"], "Expected no refresh")
+
+	// changes to workflows by others are still observed
+	fs.WriteFile(".github/workflows/other.yml", []byte("on: push\n"), 0644)
+	assert.True(t, watcher.handleEvent(fswatcher, fsnotify.Event{Name: ".github/workflows/other.yml", Op: fsnotify.Create}))
+	fs.WriteFile(".github/workflows/test.yml", []byte("# edited by hand"), 0644)
+	assert.True(t, watcher.handleEvent(fswatcher, fsnotify.Event{Name: ".github/workflows/test.yml", Op: fsnotify.Write}))
+	assert.False(t, watcher.sources[".gflows/workflows/new.jsonnet"], "Expected no refresh")
+}
+
+func TestWatcherObservesYamlWorkflows(t *testing.T) {
+	fs, watcher, fswatcher := newTestWatcher()
+	fs.WriteFile(".github/workflows/other.yaml", []byte("on: push\n"), 0644)
+	watcher.runOnChange(func() []*workflow.Definition { return nil })
+
+	assert.True(t, watcher.isUnchanged(".github/workflows/other.yaml"), "Expected unchanged workflows to be ignored")
+	assert.False(t, watcher.handleEvent(fswatcher, fsnotify.Event{Name: ".github/workflows/other.yaml", Op: fsnotify.Write}))
+
+	fs.WriteFile(".github/workflows/other.yaml", []byte("# edited by hand"), 0644)
+	assert.True(t, watcher.handleEvent(fswatcher, fsnotify.Event{Name: ".github/workflows/other.yaml", Op: fsnotify.Write}))
+}
+
+func TestWatcherDebouncesEvents(t *testing.T) {
+	_, watcher, fswatcher := newTestWatcher()
+	events := make(chan fsnotify.Event)
+	errors := make(chan error)
+	changes := 0
+	done := make(chan bool)
+	go func() {
+		watcher.watch(fswatcher, events, errors, func() []*workflow.Definition {
+			changes++
+			return nil
+		})
+		done <- true
+	}()
+
+	for i := 0; i < 5; i++ {
+		events <- fsnotify.Event{Name: ".gflows/workflows/test.jsonnet", Op: fsnotify.Write}
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(events)
+	<-done
+
+	assert.Equal(t, 1, changes)
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/jbrunton/gflows/config"
@@ -14,19 +15,20 @@ import (
 	"github.com/jbrunton/gflows/io/styles"
 	"github.com/jbrunton/gflows/workflow"
 	"github.com/jbrunton/gflows/workflow/engine"
+	"github.com/jbrunton/gflows/yamlutil"
 	statikFs "github.com/rakyll/statik/fs"
 
 	fdiff "github.com/go-git/go-git/v5/plumbing/format/diff"
 	"github.com/spf13/afero"
 )
 
-func CreateWorkflowEngine(fs *afero.Afero, context *config.GFlowsContext, contentWriter *content.Writer, env *env.GFlowsEnv, logger *io.Logger) workflow.TemplateEngine {
+func CreateWorkflowEngine(fs *afero.Afero, context *config.GFlowsContext, contentWriter *content.Writer, env *env.GFlowsEnv, logger *io.Logger, cache *workflow.BuildCache) workflow.TemplateEngine {
 	var templateEngine workflow.TemplateEngine
 	switch engineName := context.Config.Templates.Engine; engineName {
 	case "jsonnet":
-		templateEngine = engine.NewJsonnetTemplateEngine(fs, context, contentWriter, env)
+		templateEngine = engine.NewJsonnetTemplateEngine(fs, context, contentWriter, env, cache)
 	case "ytt":
-		templateEngine = engine.NewYttTemplateEngine(fs, context, contentWriter, env, logger)
+		templateEngine = engine.NewYttTemplateEngine(fs, context, contentWriter, env, logger, cache)
 	default:
 		panic(fmt.Errorf("Unexpected engine: %s", engineName))
 	}
@@ -63,114 +65,250 @@ func NewWorkflowManager(
 	}
 }
 
-func (manager *WorkflowManager) GetWorkflows() []workflow.GitHubWorkflow {
-	files := []string{}
-	files, err := afero.Glob(manager.fs, filepath.Join(manager.context.GitHubDir, "workflows/*.yml"))
+// GetWorkflows - returns the workflows in the .github/workflows directory, classified by whether
+// they're managed by gflows
+func (manager *WorkflowManager) GetWorkflows() ([]workflow.GitHubWorkflow, error) {
+	definitions, err := manager.GetWorkflowDefinitions()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
+	return manager.getWorkflows(definitions)
+}
 
-	definitions, err := manager.GetWorkflowDefinitions()
+func (manager *WorkflowManager) getWorkflows(definitions []*workflow.Definition) ([]workflow.GitHubWorkflow, error) {
+	files, err := manager.getWorkflowPaths()
 	if err != nil {
-		panic(err) // TODO: improve handling
+		return nil, err
 	}
 
 	var gitHubWorkflows []workflow.GitHubWorkflow
 
 	for _, file := range files {
-		workflow := workflow.GitHubWorkflow{Path: file}
+		gitHubWorkflow := workflow.GitHubWorkflow{Path: file, Ownership: workflow.Unmanaged}
 		for _, definition := range definitions {
 			if definition.Destination == file {
-				workflow.Definition = definition
+				gitHubWorkflow.Definition = definition
+				gitHubWorkflow.Ownership = workflow.Managed
 				break
 			}
 		}
-		gitHubWorkflows = append(gitHubWorkflows, workflow)
+		if gitHubWorkflow.Definition == nil {
+			generated, err := manager.isGenerated(file)
+			if err != nil {
+				return nil, err
+			}
+			if generated {
+				gitHubWorkflow.Ownership = workflow.Orphaned
+			}
+		}
+		gitHubWorkflows = append(gitHubWorkflows, gitHubWorkflow)
 	}
 
-	return gitHubWorkflows
+	return gitHubWorkflows, nil
 }
 
-// UpdateWorkflows - update workflow files for the given context
-func (manager *WorkflowManager) UpdateWorkflows() error {
-	definitions, err := manager.GetWorkflowDefinitions()
+// getWorkflowPaths - returns the paths of the workflow files in the GitHub workflows directory
+func (manager *WorkflowManager) getWorkflowPaths() ([]string, error) {
+	var paths []string
+	for _, extension := range workflow.WorkflowExtensions {
+		matches, err := afero.Glob(manager.fs, filepath.Join(manager.context.GitHubDir, "workflows", "*"+extension))
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, matches...)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func (manager *WorkflowManager) isGenerated(path string) (bool, error) {
+	data, err := manager.fs.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("unable to read workflow %s: %s", path, err)
+	}
+	return workflow.IsGenerated(string(data), manager.context.Config.GetAllHeaderTemplates()), nil
+}
+
+// PruneWorkflows - deletes orphaned workflows selected by the filter, i.e. workflows generated by
+// gflows for templates which no longer exist
+func (manager *WorkflowManager) PruneWorkflows(filter *workflow.WorkflowFilter) error {
+	gitHubWorkflows, err := manager.GetWorkflows()
 	if err != nil {
 		return err
 	}
+	for _, gitHubWorkflow := range gitHubWorkflows {
+		if gitHubWorkflow.Ownership != workflow.Orphaned || !filter.Matches(gitHubWorkflow.Name()) {
+			continue
+		}
+		err := manager.contentWriter.DeleteFile(gitHubWorkflow.Path, "(orphaned)")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getSelectedDefinitions - returns definitions for the workflows selected by the filter, returning
+// an error if the filter selects workflows by pattern and none match
+func (manager *WorkflowManager) getSelectedDefinitions(filter *workflow.WorkflowFilter) ([]*workflow.Definition, error) {
+	definitions, err := manager.GetWorkflowDefinitionsMatching(filter)
+	if err != nil {
+		return nil, err
+	}
+	if filter.IsSelective() && len(definitions) == 0 {
+		return nil, fmt.Errorf("No workflows matched %s", strings.Join(filter.Patterns, ", "))
+	}
+	return definitions, nil
+}
+
+// UpdateWorkflows - update workflow files for the given context, for the workflows selected by the
+// filter. All workflows are evaluated and validated before any are written, and if any are invalid
+// (or any write fails) then no workflows are updated, unless partial is true, in which case the
+// valid workflows are still written. Returns the definitions evaluated (even if there are errors),
+// so that the watcher needn't evaluate them again.
+func (manager *WorkflowManager) UpdateWorkflows(filter *workflow.WorkflowFilter, partial bool) ([]*workflow.Definition, error) {
+	definitions, err := manager.getSelectedDefinitions(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	schemaResults := make([]workflow.ValidationResult, len(definitions))
+	workflow.RunParallel(len(definitions), manager.context.Jobs, func(i int) {
+		if definitions[i].Status.Valid {
+			schemaResults[i] = manager.validator.ValidateSchema(definitions[i])
+		}
+	})
+
 	valid := true
-	for _, definition := range definitions {
+	validDefinitions := []*workflow.Definition{}
+	for i, definition := range definitions {
 		details := fmt.Sprintf("(from %s)", definition.Description)
-		if definition.Status.Valid {
-			schemaResult := manager.validator.ValidateSchema(definition)
-			if schemaResult.Valid {
-				manager.contentWriter.UpdateFileContent(definition.Destination, definition.Content, details)
-			} else {
-				manager.contentWriter.LogErrors(definition.Destination, details, schemaResult.Errors)
-				valid = false
-			}
-		} else {
+		if !definition.Status.Valid {
 			manager.contentWriter.LogErrors(definition.Destination, details, definition.Status.Errors)
 			valid = false
+			continue
+		}
+		schemaResult := schemaResults[i]
+		if !schemaResult.Valid {
+			manager.contentWriter.LogErrors(definition.Destination, details, schemaResult.Errors)
+			valid = false
+			continue
+		}
+		validDefinitions = append(validDefinitions, definition)
+	}
+
+	if !valid && !partial {
+		if len(validDefinitions) > 0 {
+			return definitions, errors.New("errors encountered generating workflows, no workflows were updated (use --partial to update the valid workflows)")
+		}
+		return definitions, errors.New("errors encountered generating workflows")
+	}
+
+	transaction := manager.contentWriter.BeginTransaction()
+	for _, definition := range validDefinitions {
+		details := fmt.Sprintf("(from %s)", definition.Description)
+		err := transaction.UpdateFileContent(definition.Destination, definition.Content, details)
+		if err == nil {
+			continue
+		}
+		manager.contentWriter.LogErrors(definition.Destination, details, []string{err.Error()})
+		if partial {
+			valid = false
+			continue
+		}
+		if rollbackErr := transaction.Rollback(); rollbackErr != nil {
+			return definitions, fmt.Errorf("unable to roll back changes after error writing %s: %s", definition.Destination, rollbackErr)
 		}
+		return definitions, errors.New("errors encountered writing workflows, no workflows were updated")
 	}
+
 	if !valid {
-		return errors.New("errors encountered generating workflows")
+		return definitions, errors.New("errors encountered generating workflows")
 	}
-	return nil
+	return definitions, nil
 }
 
-// ValidateWorkflows - returns an error if the workflows are out of date
-func (manager *WorkflowManager) ValidateWorkflows(showDiff bool) error {
-	definitions, err := manager.GetWorkflowDefinitions()
+// DiffMode - how to show the changes to out of date workflows
+type DiffMode string
+
+const (
+	// DiffModeNone - don't show diffs
+	DiffModeNone DiffMode = ""
+	// DiffModeText - show a line by line diff of the workflow files
+	DiffModeText DiffMode = "text"
+	// DiffModeSemantic - show structural changes to the parsed workflows by path
+	DiffModeSemantic DiffMode = "semantic"
+)
+
+// DiffModes - valid options for --diff-mode
+var DiffModes = []string{string(DiffModeText), string(DiffModeSemantic)}
+
+// ValidateWorkflows - returns an error if the workflows selected by the filter are out of date
+func (manager *WorkflowManager) ValidateWorkflows(filter *workflow.WorkflowFilter, showDiff bool) error {
+	reports, err := manager.GetWorkflowReports(filter)
 	if err != nil {
 		return err
 	}
+	diffMode := DiffModeNone
+	if showDiff {
+		diffMode = DiffModeText
+	}
+	return manager.PrintWorkflowReports(reports, diffMode)
+}
+
+// PrintWorkflowReports - prints the validation results for the given reports, returning an error if
+// any workflows are invalid or out of date
+func (manager *WorkflowManager) PrintWorkflowReports(reports []*WorkflowReport, diffMode DiffMode) error {
 	valid := true
-	for _, definition := range definitions {
-		manager.logger.Printf("Checking %s ... ", manager.styles.Bold(definition.Name))
+	for _, report := range reports {
+		manager.logger.Printf("Checking %s ... ", manager.styles.Bold(report.Name))
 
-		if !definition.Status.Valid {
+		if report.Status == StatusOrphaned {
+			manager.logger.Println(manager.styles.StyleError("FAILED"))
+			manager.logger.Println("  " + report.Content.Errors[0])
+			manager.logger.Println("  ► Run \"gflows prune\" to delete it")
+			valid = false
+			continue
+		}
+
+		if report.Status == StatusTemplateError {
 			manager.logger.Println(manager.styles.StyleError("FAILED"))
 			manager.logger.Println("  Error parsing template:")
-			manager.logger.PrintStatusErrors(definition.Status.Errors, false)
+			manager.logger.PrintStatusErrors(report.TemplateErrors, false)
 			valid = false
 			continue
 		}
 
-		schemaResult := manager.validator.ValidateSchema(definition)
-		if !schemaResult.Valid {
+		if len(report.SchemaErrors) > 0 {
 			manager.logger.Println(manager.styles.StyleError("FAILED"))
 			manager.logger.Println("  Schema validation failed:")
-			manager.logger.PrintStatusErrors(schemaResult.Errors, false)
+			manager.logger.PrintStatusErrors(report.SchemaErrors, false)
 			valid = false
 		}
 
-		contentResult := manager.validator.ValidateContent(definition)
-		if !contentResult.Valid {
-			if schemaResult.Valid { // otherwise we'll duplicate the failure message
+		if !report.Content.Valid {
+			if len(report.SchemaErrors) == 0 { // otherwise we'll duplicate the failure message
 				manager.logger.Println(manager.styles.StyleError("FAILED"))
 			}
-			manager.logger.Println("  " + contentResult.Errors[0])
-			manager.logger.Println("  ► Run \"gflows update\" to update")
+			manager.logger.Println("  " + report.Content.Errors[0])
+			if report.Status == StatusHandEdited {
+				manager.logger.Printfln("  ► Make the changes in the template (%s) and run \"gflows update\", or run \"gflows update\" to discard them", report.Description)
+			} else {
+				manager.logger.Println("  ► Run \"gflows update\" to update")
+			}
 			valid = false
 
-			if showDiff {
-				fpatch, err := diff.CreateFilePatch(contentResult.ActualContent, definition.Content)
-				if err != nil {
-					panic(err)
-				}
-				message := strings.Join([]string{
-					fmt.Sprintf("src: <generated from: %s>\ndst: %s", definition.Source, definition.Destination),
-					fmt.Sprintf(`This diff previews what will happen to %s if you run "gflows update"`, definition.Destination),
-				}, "\n")
-				patch := diff.NewPatch([]fdiff.FilePatch{fpatch}, message)
-				manager.logger.PrettyPrintDiff(patch.Format())
+			switch diffMode {
+			case DiffModeText:
+				manager.printTextDiff(report)
+			case DiffModeSemantic:
+				manager.printSemanticDiff(report)
 			}
 		}
 
-		if schemaResult.Valid && contentResult.Valid {
+		if report.Valid() {
 			manager.logger.Println(manager.styles.StyleOK("OK"))
-			for _, err := range append(schemaResult.Errors, contentResult.Errors...) {
+			for _, err := range report.Warnings {
 				manager.logger.Printf("  Warning: %s\n", err)
 			}
 		}
@@ -181,6 +319,54 @@ func (manager *WorkflowManager) ValidateWorkflows(showDiff bool) error {
 	return nil
 }
 
+func (manager *WorkflowManager) printTextDiff(report *WorkflowReport) {
+	definition := report.Definition
+	fpatch, err := diff.CreateFilePatch(report.ActualContent, definition.Content)
+	if err != nil {
+		panic(err)
+	}
+	message := strings.Join([]string{
+		fmt.Sprintf("src: <generated from: %s>\ndst: %s", definition.Source, definition.Destination),
+		fmt.Sprintf(`This diff previews what will happen to %s if you run "gflows update"`, definition.Destination),
+	}, "\n")
+	patch := diff.NewPatch([]fdiff.FilePatch{fpatch}, message)
+	manager.logger.PrettyPrintDiff(patch.Format())
+}
+
+// printSemanticDiff - prints the structural changes between the workflow file and the generated
+// workflow. Falls back to a text diff if the workflow file can't be parsed.
+func (manager *WorkflowManager) printSemanticDiff(report *WorkflowReport) {
+	actual, err := yamlutil.ParseYaml(report.ActualContent)
+	if err != nil {
+		manager.logger.Debugf("Could not parse %s (%s), showing text diff\n", report.Destination, err)
+		manager.printTextDiff(report)
+		return
+	}
+	generated, err := yamlutil.ParseYaml(report.Definition.Content)
+	if err != nil {
+		panic(err) // not expected, since the content was validated when generated
+	}
+	changes := diff.SemanticDiff(actual, generated)
+	if len(changes) == 0 {
+		manager.logger.Println("  No semantic changes (differences are in formatting only)")
+		return
+	}
+	manager.logger.Println("  Changes:")
+	for _, change := range changes {
+		manager.logger.Println("    " + manager.styleChange(change))
+	}
+}
+
+func (manager *WorkflowManager) styleChange(change diff.Change) string {
+	switch change.Type {
+	case diff.Added:
+		return manager.styles.StyleOK(change.String()).String()
+	case diff.Removed:
+		return manager.styles.StyleError(change.String())
+	}
+	return change.String()
+}
+
 func (manager *WorkflowManager) InitWorkflows(workflowName string, githubDir string, configPath string) {
 	jobName := "check-workflows"
 	if workflowName != "gflows" {
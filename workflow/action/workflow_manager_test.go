@@ -3,6 +3,7 @@ package action
 import (
 	"bytes"
 	"net/http"
+	"os"
 	"strings"
 	"testing"
 
@@ -20,12 +21,14 @@ func newTestWorkflowManager() (*afero.Afero, *bytes.Buffer, *WorkflowManager) {
 	ioContainer, context, out := fixtures.NewTestContext("templates:\n  engine: jsonnet")
 	httpClient := &http.Client{Transport: fixtures.NewMockRoundTripper()}
 	fs := ioContainer.FileSystem()
-	validator := workflow.NewValidator(fs, context)
 	container := content.NewContainer(ioContainer, httpClient)
+	schemaCache := workflow.NewSchemaCache(fs, container.ContentReader(), container.Logger(), context)
+	buildCache := workflow.NewBuildCache(fs, container.Logger(), context)
+	validator := workflow.NewValidator(fs, schemaCache, buildCache, context)
 	repoManager := content.NewRepoManager(container.GitAdapter(), container.FileSystem(), container.Logger())
 	installer := env.NewGFlowsLibInstaller(container.FileSystem(), container.ContentReader(), container.ContentWriter(), container.Logger(), repoManager)
 	env := env.NewGFlowsEnv(fs, installer, context, container.Logger())
-	templateEngine := CreateWorkflowEngine(fs, context, container.ContentWriter(), env, container.Logger())
+	templateEngine := CreateWorkflowEngine(fs, context, container.ContentWriter(), env, container.Logger(), buildCache)
 	return fs, out, NewWorkflowManager(
 		fs,
 		container.Logger(),
@@ -41,9 +44,105 @@ func TestGetUnimportedWorkflows(t *testing.T) {
 	fs, _, workflowManager := newTestWorkflowManager()
 	fs.WriteFile(".github/workflows/workflow.yml", []byte(fixtures.ExampleWorkflow("test.jsonnet")), 0644)
 
-	gitHubWorkflows := workflowManager.GetWorkflows()
+	gitHubWorkflows, err := workflowManager.GetWorkflows()
 
-	assert.Equal(t, []workflow.GitHubWorkflow{workflow.GitHubWorkflow{Path: ".github/workflows/workflow.yml"}}, gitHubWorkflows)
+	assert.NoError(t, err)
+	assert.Equal(t, []workflow.GitHubWorkflow{workflow.GitHubWorkflow{Path: ".github/workflows/workflow.yml", Ownership: workflow.Orphaned}}, gitHubWorkflows)
+}
+
+func TestGetUnimportedYamlWorkflows(t *testing.T) {
+	fs, _, workflowManager := newTestWorkflowManager()
+	fs.WriteFile(".github/workflows/workflow.yaml", []byte(fixtures.ExampleWorkflow("test.jsonnet")), 0644)
+	fs.WriteFile(".github/workflows/workflow.yml", []byte("on: push\n"), 0644)
+
+	gitHubWorkflows, err := workflowManager.GetWorkflows()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []workflow.GitHubWorkflow{
+		workflow.GitHubWorkflow{Path: ".github/workflows/workflow.yaml", Ownership: workflow.Orphaned},
+		workflow.GitHubWorkflow{Path: ".github/workflows/workflow.yml", Ownership: workflow.Unmanaged},
+	}, gitHubWorkflows)
+}
+
+// unreadableFs - a filesystem where the file at path can't be opened
+type unreadableFs struct {
+	afero.Fs
+	path string
+}
+
+func (fs *unreadableFs) Open(name string) (afero.File, error) {
+	if name == fs.path {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrPermission}
+	}
+	return fs.Fs.Open(name)
+}
+
+func TestGetWorkflowsReadError(t *testing.T) {
+	fs, _, workflowManager := newTestWorkflowManager()
+	fs.WriteFile(".github/workflows/broken.yml", []byte("on: push\n"), 0644)
+	fs.Fs = &unreadableFs{Fs: fs.Fs, path: ".github/workflows/broken.yml"}
+
+	_, err := workflowManager.GetWorkflows()
+	assert.EqualError(t, err, "unable to read workflow .github/workflows/broken.yml: open .github/workflows/broken.yml: permission denied")
+
+	err = workflowManager.PruneWorkflows(nil)
+	assert.EqualError(t, err, "unable to read workflow .github/workflows/broken.yml: open .github/workflows/broken.yml: permission denied")
+}
+
+func TestGetOrphanedWorkflowsWithCustomHeader(t *testing.T) {
+	fs, _, workflowManager := newTestWorkflowManager()
+	workflowManager.context.Config.Workflows.Defaults.Header.Template = "# Generated from {{ .Source }}"
+	fs.WriteFile(".github/workflows/renamed.yml", []byte("# Generated from .gflows/workflows/renamed.jsonnet\non: push\n"), 0644)
+
+	gitHubWorkflows, err := workflowManager.GetWorkflows()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []workflow.GitHubWorkflow{workflow.GitHubWorkflow{Path: ".github/workflows/renamed.yml", Ownership: workflow.Orphaned}}, gitHubWorkflows)
+}
+
+func TestGetUnmanagedWorkflows(t *testing.T) {
+	fs, _, workflowManager := newTestWorkflowManager()
+	fs.WriteFile(".github/workflows/workflow.yml", []byte("on: push\n"), 0644)
+
+	gitHubWorkflows, err := workflowManager.GetWorkflows()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []workflow.GitHubWorkflow{workflow.GitHubWorkflow{Path: ".github/workflows/workflow.yml", Ownership: workflow.Unmanaged}}, gitHubWorkflows)
+}
+
+func TestPruneWorkflows(t *testing.T) {
+	fs, out, workflowManager := newTestWorkflowManager()
+	fs.WriteFile(".gflows/workflows/test.jsonnet", []byte(fixtures.ExampleJsonnetTemplate), 0644)
+	fs.WriteFile(".github/workflows/test.yml", []byte(fixtures.ExampleWorkflow("test.jsonnet")), 0644)
+	fs.WriteFile(".github/workflows/orphaned.yml", []byte(fixtures.ExampleWorkflow("orphaned.jsonnet")), 0644)
+	fs.WriteFile(".github/workflows/unmanaged.yml", []byte("on: push\n"), 0644)
+
+	err := workflowManager.PruneWorkflows(nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "     delete .github/workflows/orphaned.yml (orphaned)\n", out.String())
+	for path, expected := range map[string]bool{
+		".github/workflows/test.yml":      true,
+		".github/workflows/orphaned.yml":  false,
+		".github/workflows/unmanaged.yml": true,
+	} {
+		exists, _ := fs.Exists(path)
+		assert.Equal(t, expected, exists, path)
+	}
+}
+
+func TestPruneWorkflowsWithFilter(t *testing.T) {
+	fs, out, workflowManager := newTestWorkflowManager()
+	fs.WriteFile(".github/workflows/deploy-staging.yml", []byte(fixtures.ExampleWorkflow("deploy-staging.jsonnet")), 0644)
+	fs.WriteFile(".github/workflows/orphaned.yml", []byte(fixtures.ExampleWorkflow("orphaned.jsonnet")), 0644)
+
+	filter, _ := workflow.NewWorkflowFilter([]string{"deploy-*"}, nil)
+	err := workflowManager.PruneWorkflows(filter)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "     delete .github/workflows/deploy-staging.yml (orphaned)\n", out.String())
+	exists, _ := fs.Exists(".github/workflows/orphaned.yml")
+	assert.True(t, exists, "expected orphans not selected by the filter to be kept")
 }
 
 func TestGetImportedWorkflows(t *testing.T) {
@@ -51,21 +150,26 @@ func TestGetImportedWorkflows(t *testing.T) {
 	fs.WriteFile(".gflows/workflows/test.jsonnet", []byte(fixtures.ExampleJsonnetTemplate), 0644)
 	fs.WriteFile(".github/workflows/test.yml", []byte(fixtures.ExampleWorkflow("test.jsonnet")), 0644)
 
-	gitHubWorkflows := workflowManager.GetWorkflows()
+	gitHubWorkflows, err := workflowManager.GetWorkflows()
 
+	assert.NoError(t, err)
 	expectedContent := fixtures.ExampleWorkflow("test.jsonnet")
 	expectedJson, _ := yamlutil.YamlToJson(expectedContent)
 	expectedWorflow := workflow.GitHubWorkflow{
 		Path: ".github/workflows/test.yml",
 		Definition: &workflow.Definition{
-			Name:        "test",
-			Source:      ".gflows/workflows/test.jsonnet",
-			Description: ".gflows/workflows/test.jsonnet",
-			Destination: ".github/workflows/test.yml",
-			Content:     expectedContent,
-			Status:      workflow.ValidationResult{Valid: true},
-			JSON:        expectedJson,
+			Name:         "test",
+			Source:       ".gflows/workflows/test.jsonnet",
+			Description:  ".gflows/workflows/test.jsonnet",
+			Destination:  ".github/workflows/test.yml",
+			Content:      expectedContent,
+			Header:       "# File generated by gflows, do not modify\n# Source: .gflows/workflows/test.jsonnet\n",
+			Dependencies: []string{".gflows/workflows/test.jsonnet"},
+			Status:       workflow.ValidationResult{Valid: true},
+			JSON:         expectedJson,
+			Positions:    gitHubWorkflows[0].Definition.Positions,
 		},
+		Ownership: workflow.Managed,
 	}
 	assert.Equal(t, []workflow.GitHubWorkflow{expectedWorflow}, gitHubWorkflows)
 }
@@ -133,7 +237,7 @@ Checking test ... OK
 		for _, file := range scenario.files {
 			file.Write(fs)
 		}
-		err := workflowManager.ValidateWorkflows(false)
+		err := workflowManager.ValidateWorkflows(nil, false)
 		if scenario.expectedError == "" {
 			assert.NoError(t, err, "Unexpected error for scenario %q", scenario.description)
 		} else {
@@ -146,19 +250,19 @@ Checking test ... OK
 
 	// 	// invalid template
 	// 	fs.WriteFile(".gflows/workflows/test.jsonnet", []byte(invalidJsonnetTemplate), 0644)
-	// 	workflowManager.ValidateWorkflows(false)
+	// 	workflowManager.ValidateWorkflows(nil, false)
 
 	// 	// valid template, missing workflow
 	// 	fs.WriteFile(".gflows/workflows/test.jsonnet", []byte(exampleJsonnetTemplate), 0644)
-	// 	workflowManager.ValidateWorkflows(false)
+	// 	workflowManager.ValidateWorkflows(nil, false)
 
 	// 	// valid template, out of date workflow
 	// 	fs.WriteFile(".github/workflows/test.yml", []byte("incorrect content"), 0644)
-	// 	workflowManager.ValidateWorkflows(false)
+	// 	workflowManager.ValidateWorkflows(nil, false)
 
 	// 	// valid template, up to date workflow
 	// 	fs.WriteFile(".github/workflows/test.yml", []byte(exampleWorkflow("test.jsonnet")), 0644)
-	// 	workflowManager.ValidateWorkflows(false)
+	// 	workflowManager.ValidateWorkflows(nil, false)
 
 	// 	expected := `
 	// Checking test ... FAILED
@@ -184,9 +288,10 @@ func TestUpdateWorkflows(t *testing.T) {
 	fs.WriteFile(".gflows/workflows/test2.jsonnet", []byte(fixtures.ExampleJsonnetTemplate), 0644)
 	fs.WriteFile(".github/workflows/test.yml", []byte("out of date workflow"), 0644)
 
-	err := workflowManager.UpdateWorkflows()
+	definitions, err := workflowManager.UpdateWorkflows(nil, false)
 
 	assert.NoError(t, err)
+	assert.Len(t, definitions, 2)
 	assert.Equal(t, strings.Join([]string{
 		"     update .github/workflows/test.yml (from .gflows/workflows/test.jsonnet)",
 		"     create .github/workflows/test2.yml (from .gflows/workflows/test2.jsonnet)",
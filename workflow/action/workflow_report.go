@@ -0,0 +1,189 @@
+package action
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/jbrunton/gflows/workflow"
+	"gopkg.in/yaml.v2"
+)
+
+// WorkflowStatus - summary of the validation status of a workflow
+type WorkflowStatus string
+
+const (
+	StatusUpToDate      WorkflowStatus = "up_to_date"
+	StatusOutOfDate     WorkflowStatus = "out_of_date"
+	StatusInvalidSchema WorkflowStatus = "invalid_schema"
+	StatusTemplateError WorkflowStatus = "template_error"
+	StatusOrphaned      WorkflowStatus = "orphaned"
+	StatusHandEdited    WorkflowStatus = "hand_edited"
+)
+
+// Description - returns the status as displayed in the text output of commands
+func (status WorkflowStatus) Description() string {
+	switch status {
+	case StatusUpToDate:
+		return "UP TO DATE"
+	case StatusOutOfDate:
+		return "OUT OF DATE"
+	case StatusInvalidSchema:
+		return "INVALID SCHEMA"
+	case StatusTemplateError:
+		return "TEMPLATE ERROR"
+	case StatusOrphaned:
+		return "ORPHANED"
+	case StatusHandEdited:
+		return "EDITED BY HAND"
+	}
+	return string(status)
+}
+
+// ContentCheckReport - the result of checking a generated workflow is up to date
+type ContentCheckReport struct {
+	Valid  bool     `json:"valid" yaml:"valid"`
+	Errors []string `json:"errors" yaml:"errors"`
+}
+
+// WorkflowReport - the result of validating a workflow definition, used for machine readable
+// output
+type WorkflowReport struct {
+	Name           string              `json:"name" yaml:"name"`
+	Source         string              `json:"source" yaml:"source"`
+	Description    string              `json:"description" yaml:"description"`
+	Commit         string              `json:"commit,omitempty" yaml:"commit,omitempty"`
+	Destination    string              `json:"destination" yaml:"destination"`
+	Status         WorkflowStatus      `json:"status" yaml:"status"`
+	TemplateErrors []string            `json:"templateErrors" yaml:"templateErrors"`
+	SchemaErrors   []string            `json:"schemaErrors" yaml:"schemaErrors"`
+	Content        *ContentCheckReport `json:"content" yaml:"content"`
+	Warnings       []string            `json:"warnings" yaml:"warnings"`
+
+	Definition    *workflow.Definition `json:"-" yaml:"-"`
+	ActualContent string               `json:"-" yaml:"-"`
+}
+
+// GetReportDefinitions - returns the definitions the reports were generated from (orphaned
+// workflows have none)
+func GetReportDefinitions(reports []*WorkflowReport) []*workflow.Definition {
+	definitions := []*workflow.Definition{}
+	for _, report := range reports {
+		if report.Definition != nil {
+			definitions = append(definitions, report.Definition)
+		}
+	}
+	return definitions
+}
+
+// Valid - returns true if the workflow is up to date and free of errors
+func (report *WorkflowReport) Valid() bool {
+	return report.Status == StatusUpToDate
+}
+
+// ReportFormats - the valid options for the --format flag
+var ReportFormats = []string{"text", "json", "yaml"}
+
+// CheckFormats - the valid options for the --format flag of the check command, which can also
+// emit GitHub annotations
+var CheckFormats = []string{"text", "json", "yaml", "github"}
+
+// FormatReports - serializes the reports in the given format (json, yaml or github)
+func FormatReports(reports []*WorkflowReport, format string) (string, error) {
+	switch format {
+	case "json":
+		out, err := json.MarshalIndent(reports, "", "  ")
+		return string(out) + "\n", err
+	case "yaml":
+		out, err := yaml.Marshal(reports)
+		return string(out), err
+	case "github":
+		return formatAnnotations(reports), nil
+	}
+	return "", fmt.Errorf("Unexpected format: %q", format)
+}
+
+// GetWorkflowReports - validates the workflow definitions selected by the filter and returns a
+// report for each, followed by a report for each selected orphaned workflow
+func (manager *WorkflowManager) GetWorkflowReports(filter *workflow.WorkflowFilter) ([]*WorkflowReport, error) {
+	definitions, err := manager.getSelectedDefinitions(filter)
+	if err != nil {
+		return nil, err
+	}
+	// validation is independent for each definition, so run it in parallel
+	reports := make([]*WorkflowReport, len(definitions))
+	workflow.RunParallel(len(definitions), manager.context.Jobs, func(i int) {
+		reports[i] = manager.getWorkflowReport(definitions[i])
+	})
+
+	gitHubWorkflows, err := manager.getWorkflows(definitions)
+	if err != nil {
+		return nil, err
+	}
+	for _, gitHubWorkflow := range gitHubWorkflows {
+		name := gitHubWorkflow.Name()
+		if gitHubWorkflow.Ownership != workflow.Orphaned || !filter.Matches(name) {
+			continue
+		}
+		reports = append(reports, &WorkflowReport{
+			Name:           name,
+			Destination:    gitHubWorkflow.Path,
+			Status:         StatusOrphaned,
+			TemplateErrors: []string{},
+			SchemaErrors:   []string{},
+			Content: &ContentCheckReport{
+				Valid:  false,
+				Errors: []string{fmt.Sprintf("Workflow %s was generated by gflows but has no template", gitHubWorkflow.Path)},
+			},
+			Warnings: []string{},
+		})
+	}
+	return reports, nil
+}
+
+// getWorkflowReport - validates the definition and returns a report for it
+func (manager *WorkflowManager) getWorkflowReport(definition *workflow.Definition) *WorkflowReport {
+	report := &WorkflowReport{
+		Name:           definition.Name,
+		Source:         definition.Source,
+		Description:    definition.Description,
+		Commit:         definition.Commit,
+		Destination:    definition.Destination,
+		TemplateErrors: []string{},
+		SchemaErrors:   []string{},
+		Warnings:       []string{},
+		Definition:     definition,
+	}
+
+	if !definition.Status.Valid {
+		report.Status = StatusTemplateError
+		report.TemplateErrors = definition.Status.Errors
+		return report
+	}
+
+	schemaResult := manager.validator.ValidateSchema(definition)
+	if schemaResult.Valid {
+		report.Warnings = append(report.Warnings, schemaResult.Errors...)
+	} else {
+		report.SchemaErrors = schemaResult.Errors
+	}
+
+	contentResult := manager.validator.ValidateContent(definition)
+	report.Content = &ContentCheckReport{Valid: contentResult.Valid, Errors: []string{}}
+	report.ActualContent = contentResult.ActualContent
+	if contentResult.Valid {
+		report.Warnings = append(report.Warnings, contentResult.Errors...)
+	} else {
+		report.Content.Errors = contentResult.Errors
+	}
+
+	if !schemaResult.Valid {
+		report.Status = StatusInvalidSchema
+	} else if contentResult.HandEdited {
+		report.Status = StatusHandEdited
+	} else if !contentResult.Valid {
+		report.Status = StatusOutOfDate
+	} else {
+		report.Status = StatusUpToDate
+	}
+	return report
+}
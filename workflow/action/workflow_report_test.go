@@ -0,0 +1,56 @@
+package action
+
+import (
+	"testing"
+
+	"github.com/jbrunton/gflows/fixtures"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetWorkflowReports(t *testing.T) {
+	fs, _, workflowManager := newTestWorkflowManager()
+	fs.WriteFile(".gflows/workflows/invalid.jsonnet", []byte("{}"), 0644)
+	fs.WriteFile(".gflows/workflows/test.jsonnet", []byte(fixtures.ExampleJsonnetTemplate), 0644)
+	fs.WriteFile(".github/workflows/test.yml", []byte(fixtures.ExampleWorkflow("test.jsonnet")), 0644)
+
+	reports, err := workflowManager.GetWorkflowReports(nil)
+
+	assert.NoError(t, err)
+	assert.Len(t, reports, 2)
+
+	assert.Equal(t, "invalid", reports[0].Name)
+	assert.Equal(t, StatusTemplateError, reports[0].Status)
+	assert.Len(t, reports[0].TemplateErrors, 1)
+	assert.Nil(t, reports[0].Content)
+
+	assert.Equal(t, "test", reports[1].Definition.Name)
+	assert.Equal(t, &WorkflowReport{
+		Name:           "test",
+		Source:         ".gflows/workflows/test.jsonnet",
+		Description:    ".gflows/workflows/test.jsonnet",
+		Destination:    ".github/workflows/test.yml",
+		Status:         StatusUpToDate,
+		TemplateErrors: []string{},
+		SchemaErrors:   []string{},
+		Content:        &ContentCheckReport{Valid: true, Errors: []string{}},
+		Warnings:       []string{},
+		Definition:     reports[1].Definition,
+	}, reports[1])
+}
+
+func TestFormatReports(t *testing.T) {
+	reports := []*WorkflowReport{
+		&WorkflowReport{Name: "test", Status: StatusOutOfDate},
+	}
+
+	output, err := FormatReports(reports, "yaml")
+	assert.NoError(t, err)
+	assert.Contains(t, output, "status: out_of_date\n")
+
+	output, err = FormatReports(reports, "json")
+	assert.NoError(t, err)
+	assert.Contains(t, output, `"status": "out_of_date"`)
+
+	_, err = FormatReports(reports, "xml")
+	assert.EqualError(t, err, `Unexpected format: "xml"`)
+}
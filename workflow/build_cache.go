@@ -0,0 +1,249 @@
+package workflow
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+
+	"github.com/jbrunton/gflows/config"
+	"github.com/jbrunton/gflows/io"
+	"github.com/jbrunton/gflows/io/pkg"
+	"github.com/jbrunton/gflows/yamlutil"
+	"github.com/spf13/afero"
+)
+
+// engineModules - the modules which implement each template engine, whose versions are included in
+// cache keys
+var engineModules = map[string]string{
+	"jsonnet": "github.com/google/go-jsonnet",
+	"ytt":     "github.com/k14s/ytt",
+}
+
+// BuildCache - caches generated workflow definitions and schema validation results on disk so
+// that unchanged workflows needn't be evaluated and validated again.
+//
+// Definitions are keyed by everything that affects evaluation other than the files the template
+// reads (the gflows and engine versions, the config, the template and the files in its search
+// paths). Each entry records the files read when it was generated, along with hashes of their
+// content, and is only used if those files are unchanged. Validation results are keyed by the
+// schema (along with any schemas it references) and the generated content.
+type BuildCache struct {
+	fs       *afero.Afero
+	logger   *io.Logger
+	context  *config.GFlowsContext
+	cacheDir string
+}
+
+type definitionCacheEntry struct {
+	Inputs     map[string]string
+	Definition *Definition
+}
+
+// NewBuildCache - creates a new build cache, stored in the user cache dir
+func NewBuildCache(fs *afero.Afero, logger *io.Logger, context *config.GFlowsContext) *BuildCache {
+	return &BuildCache{
+		fs:       fs,
+		logger:   logger,
+		context:  context,
+		cacheDir: io.CacheDir("builds"),
+	}
+}
+
+// Enabled - returns false if the cache was disabled (with --no-cache)
+func (cache *BuildCache) Enabled() bool {
+	return !cache.context.NoCache
+}
+
+// DefinitionKey - returns the key for the definition of the given workflow. searchPaths are the
+// directories (or files) the engine may read imports from: since adding a file to a search path
+// may change which file an import resolves to, the key includes a listing of them.
+func (cache *BuildCache) DefinitionKey(engine string, workflowName string, template *pkg.PathInfo, searchPaths []string) (string, error) {
+	if !cache.Enabled() {
+		return "", nil
+	}
+	listing := []string{}
+	for _, searchPath := range searchPaths {
+		exists, err := cache.fs.Exists(searchPath)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			continue
+		}
+		err = cache.fs.Walk(searchPath, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			listing = append(listing, path)
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return hashKey(map[string]interface{}{
+		"version":       cache.context.Version,
+		"engine":        engine,
+		"engineVersion": moduleVersion(engineModules[engine]),
+		"config":        cache.context.Config,
+		"contextDir":    cache.context.Dir,
+		"githubDir":     cache.context.GitHubDir,
+		"workflow":      workflowName,
+		"template":      template,
+		"listing":       listing,
+	})
+}
+
+// GetDefinition - returns the cached definition for the key, or nil if there isn't one or if any
+// of the files read to generate it have changed
+func (cache *BuildCache) GetDefinition(key string) *Definition {
+	if !cache.Enabled() {
+		return nil
+	}
+	entry := &definitionCacheEntry{}
+	if !cache.read(cache.definitionPath(key), entry) || entry.Definition == nil {
+		return nil
+	}
+	for path, hash := range entry.Inputs {
+		actualHash, err := cache.hashFile(path)
+		if err != nil || actualHash != hash {
+			cache.logger.Debugf("Build cache miss for %s (%s changed)\n", entry.Definition.Name, path)
+			return nil
+		}
+	}
+
+	definition := entry.Definition
+	json, err := yamlutil.YamlToJson(definition.Content)
+	if err != nil {
+		return nil
+	}
+	definition.JSON = json
+	cache.logger.Debugf("Build cache hit for %s\n", definition.Name)
+	return definition
+}
+
+// PutDefinition - caches the definition. Invalid definitions aren't cached, since the error may be
+// caused by a file which doesn't exist yet (and so isn't recorded as a dependency).
+func (cache *BuildCache) PutDefinition(key string, definition *Definition) {
+	if !cache.Enabled() || !definition.Status.Valid {
+		return
+	}
+	inputs := make(map[string]string)
+	for _, path := range definition.Dependencies {
+		hash, err := cache.hashFile(path)
+		if err != nil {
+			return
+		}
+		inputs[path] = hash
+	}
+
+	// JSON is derived from the content, and wouldn't round trip with the same types
+	cached := *definition
+	cached.JSON = nil
+	cache.write(cache.definitionPath(key), &definitionCacheEntry{Inputs: inputs, Definition: &cached})
+}
+
+// ValidationKey - returns the key for the result of validating the definition against the given
+// schema sources (i.e. the schema and any schemas it references, by URI)
+func (cache *BuildCache) ValidationKey(definition *Definition, schemaSources map[string]string) (string, error) {
+	schemas := make(map[string]string)
+	for uri, source := range schemaSources {
+		schemas[uri] = fmt.Sprintf("%x", sha256.Sum256([]byte(source)))
+	}
+	return hashKey(map[string]interface{}{
+		"version":   cache.context.Version,
+		"schemas":   schemas,
+		"name":      definition.Name,
+		"content":   definition.Content,
+		"positions": definition.Positions,
+	})
+}
+
+// GetValidation - returns the cached validation result for the key, or nil if there isn't one
+func (cache *BuildCache) GetValidation(key string) *ValidationResult {
+	if !cache.Enabled() {
+		return nil
+	}
+	result := &ValidationResult{}
+	if !cache.read(cache.validationPath(key), result) {
+		return nil
+	}
+	return result
+}
+
+// PutValidation - caches the validation result
+func (cache *BuildCache) PutValidation(key string, result ValidationResult) {
+	if !cache.Enabled() {
+		return
+	}
+	cache.write(cache.validationPath(key), &result)
+}
+
+func (cache *BuildCache) definitionPath(key string) string {
+	return filepath.Join(cache.cacheDir, "definitions", key+".json")
+}
+
+func (cache *BuildCache) validationPath(key string) string {
+	return filepath.Join(cache.cacheDir, "validations", key+".json")
+}
+
+func (cache *BuildCache) read(path string, value interface{}) bool {
+	data, err := cache.fs.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	// a corrupt entry is treated as a miss, and will be overwritten
+	return json.Unmarshal(data, value) == nil
+}
+
+func (cache *BuildCache) write(path string, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		panic(err)
+	}
+	// the cache is an optimisation, so failing to write to it isn't an error
+	err = cache.fs.MkdirAll(filepath.Dir(path), 0755)
+	if err == nil {
+		err = cache.fs.WriteFile(path, data, 0644)
+	}
+	if err != nil {
+		cache.logger.Debugf("Unable to write to build cache: %s\n", err)
+	}
+}
+
+func (cache *BuildCache) hashFile(path string) (string, error) {
+	data, err := cache.fs.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
+
+// hashKey - returns a hash of the JSON serialization of the value. Map keys are serialized in
+// sorted order, so equal values have equal hashes.
+func hashKey(value map[string]interface{}) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
+
+// moduleVersion - returns the version of the given module compiled into the binary, or an empty
+// string if it isn't known (e.g. in tests)
+func moduleVersion(path string) string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == path {
+			return dep.Version
+		}
+	}
+	return ""
+}
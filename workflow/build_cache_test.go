@@ -0,0 +1,111 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/jbrunton/gflows/fixtures"
+	"github.com/jbrunton/gflows/io/pkg"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestBuildCache() (*afero.Afero, *BuildCache) {
+	container, context, _ := fixtures.NewTestContext("")
+	fs := container.FileSystem()
+	return fs, NewBuildCache(fs, container.Logger(), context)
+}
+
+func newCachedDefinition(fs *afero.Afero) *Definition {
+	fs.WriteFile(".gflows/workflows/test/config.yml", []byte("'on': push"), 0644)
+	fs.WriteFile(".gflows/libs/steps.lib.yml", []byte("#@ steps = []"), 0644)
+	definition := &Definition{
+		Name:         "test",
+		Source:       ".gflows/workflows/test",
+		Destination:  ".github/workflows/test.yml",
+		Dependencies: []string{".gflows/libs/steps.lib.yml", ".gflows/workflows/test/config.yml"},
+		Status:       ValidationResult{Valid: true},
+	}
+	definition.SetContent("\"on\": push\n", &pkg.PathInfo{LocalPath: ".gflows/workflows/test", Description: ".gflows/workflows/test"}, ContentOptions{})
+	return definition
+}
+
+func TestBuildCacheDefinitions(t *testing.T) {
+	fs, cache := newTestBuildCache()
+	template := &pkg.PathInfo{LocalPath: ".gflows/workflows/test"}
+	definition := newCachedDefinition(fs)
+	key, err := cache.DefinitionKey("ytt", "test", template, []string{".gflows/workflows/test", ".gflows/libs"})
+	assert.NoError(t, err)
+
+	assert.Nil(t, cache.GetDefinition(key))
+
+	cache.PutDefinition(key, definition)
+	assert.Equal(t, definition, cache.GetDefinition(key))
+
+	// changing a dependency invalidates the entry
+	fs.WriteFile(".gflows/libs/steps.lib.yml", []byte("#@ steps = [{'run': 'make'}]"), 0644)
+	assert.Nil(t, cache.GetDefinition(key))
+}
+
+func TestBuildCacheDefinitionKey(t *testing.T) {
+	fs, cache := newTestBuildCache()
+	template := &pkg.PathInfo{LocalPath: ".gflows/workflows/test"}
+	newCachedDefinition(fs)
+	searchPaths := []string{".gflows/workflows/test", ".gflows/libs"}
+	key, _ := cache.DefinitionKey("ytt", "test", template, searchPaths)
+
+	sameKey, _ := cache.DefinitionKey("ytt", "test", template, searchPaths)
+	assert.Equal(t, key, sameKey)
+
+	// adding a file to a search path may change how imports resolve
+	fs.WriteFile(".gflows/libs/other.lib.yml", []byte(""), 0644)
+	newKey, _ := cache.DefinitionKey("ytt", "test", template, searchPaths)
+	assert.NotEqual(t, key, newKey)
+
+	otherWorkflowKey, _ := cache.DefinitionKey("ytt", "other", template, searchPaths)
+	assert.NotEqual(t, newKey, otherWorkflowKey)
+
+	cache.context.Version = "1.2.3"
+	versionKey, _ := cache.DefinitionKey("ytt", "test", template, searchPaths)
+	assert.NotEqual(t, newKey, versionKey)
+}
+
+func TestBuildCacheIgnoresInvalidDefinitions(t *testing.T) {
+	fs, cache := newTestBuildCache()
+	definition := newCachedDefinition(fs)
+	definition.Status = ValidationResult{Valid: false, Errors: []string{"couldn't open import"}}
+
+	cache.PutDefinition("key", definition)
+
+	assert.Nil(t, cache.GetDefinition("key"))
+}
+
+func TestBuildCacheValidations(t *testing.T) {
+	fs, cache := newTestBuildCache()
+	definition := newCachedDefinition(fs)
+	key, err := cache.ValidationKey(definition, map[string]string{"": "{}"})
+	assert.NoError(t, err)
+	result := ValidationResult{Valid: false, Errors: []string{"jobs is required"}}
+
+	assert.Nil(t, cache.GetValidation(key))
+	cache.PutValidation(key, result)
+	assert.Equal(t, &result, cache.GetValidation(key))
+
+	otherSchemaKey, _ := cache.ValidationKey(definition, map[string]string{"": `{"type": "object"}`})
+	assert.NotEqual(t, key, otherSchemaKey)
+	otherRefKey, _ := cache.ValidationKey(definition, map[string]string{"": "{}", "https://example.com/defs.json": "{}"})
+	assert.NotEqual(t, key, otherRefKey)
+}
+
+func TestBuildCacheDisabled(t *testing.T) {
+	fs, cache := newTestBuildCache()
+	cache.context.NoCache = true
+	definition := newCachedDefinition(fs)
+
+	cache.PutDefinition("key", definition)
+	cache.PutValidation("key", definition.Status)
+
+	assert.Nil(t, cache.GetDefinition("key"))
+	assert.Nil(t, cache.GetValidation("key"))
+	exists, _ := fs.DirExists(cache.cacheDir)
+	assert.False(t, exists)
+}
@@ -2,8 +2,10 @@ package workflow
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
+	"github.com/jbrunton/gflows/config"
 	"github.com/jbrunton/gflows/io/pkg"
 
 	"github.com/jbrunton/gflows/yamlutil"
@@ -18,22 +20,100 @@ type Definition struct {
 	Content     string
 	JSON        interface{}
 	Status      ValidationResult
+
+	// Commit - the SHA of the commit installed, for templates from git packages
+	Commit string
+
+	// Dependencies - the files read while evaluating the template (including the template itself),
+	// sorted. Recorded even if evaluation failed.
+	Dependencies []string
+
+	// Header - the comments at the start of Content (as configured for the workflow)
+	Header string
+
+	// Positions - the location of each node in the workflow, keyed by its path (e.g.
+	// "jobs.build.steps.0"). Engines which can trace nodes back to their templates point at the
+	// template, otherwise positions are in the generated content.
+	Positions map[string]*SourcePosition
 }
 
-func (definition *Definition) SetContent(workflow string, template *pkg.PathInfo) {
-	meta := strings.Join([]string{
-		"# File generated by gflows, do not modify",
-		fmt.Sprintf("# Source: %s", template.Description),
-	}, "\n")
-	definition.Content = meta + "\n" + workflow
+// SourcePosition - a location in a file. Col is zero if unknown.
+type SourcePosition struct {
+	File string
+	Line int
+	Col  int
+}
 
-	json, err := yamlutil.YamlToJson(definition.Content)
+// String - formats the position as file:line:col
+func (position *SourcePosition) String() string {
+	if position.Col > 0 {
+		return fmt.Sprintf("%s:%d:%d", position.File, position.Line, position.Col)
+	}
+	return fmt.Sprintf("%s:%d", position.File, position.Line)
+}
+
+var yamlErrorLine = regexp.MustCompile(`^yaml: line (\d+):`)
+
+// SetContent - sets the generated content (adding the configured header), parsing it to validate it
+// and find the position of each node. If the key order is config.KeyOrderGitHub then keys are first
+// reordered to follow GitHub's conventions.
+func (definition *Definition) SetContent(workflow string, template *pkg.PathInfo, opts ContentOptions) {
+	if opts.KeyOrder == config.KeyOrderGitHub {
+		// if the content is invalid then leave it as is, and report the error below
+		if ordered, err := yamlutil.OrderWorkflowKeys(workflow); err == nil {
+			workflow = ordered
+		}
+	}
+
+	header, err := opts.renderHeader(definition.Name, workflow, template)
 	if err != nil {
 		definition.Status = ValidationResult{
 			Valid:  false,
 			Errors: []string{err.Error()},
 		}
-	} else {
-		definition.JSON = json
+		return
+	}
+	definition.Header = header
+	definition.Content = header + workflow
+
+	json, err := yamlutil.YamlToJson(definition.Content)
+	if err != nil {
+		message := err.Error()
+		if matches := yamlErrorLine.FindStringSubmatch(message); matches != nil {
+			// the line refers to the generated content
+			message = fmt.Sprintf("%s:%s: %s", definition.Destination, matches[1], message)
+		}
+		definition.Status = ValidationResult{
+			Valid:  false,
+			Errors: []string{message},
+		}
+		return
+	}
+	definition.JSON = json
+
+	positions, err := yamlutil.FindPositions(definition.Content)
+	if err != nil {
+		// not expected if the content parsed above, and positions are only used to annotate errors
+		return
+	}
+	definition.Positions = make(map[string]*SourcePosition)
+	for path, position := range positions {
+		definition.Positions[path] = &SourcePosition{File: definition.Destination, Line: position.Line, Col: position.Col}
+	}
+}
+
+// Body - returns the generated content without the gflows header
+func (definition *Definition) Body() string {
+	return strings.TrimPrefix(definition.Content, definition.Header)
+}
+
+// FindPosition - returns the position of the given field (as reported by gojsonschema) or of the
+// given property of the field if there is one, or nil if the position isn't known
+func (definition *Definition) FindPosition(field string, property string) *SourcePosition {
+	if property != "" {
+		if position := definition.Positions[yamlutil.JoinPath(field, property)]; position != nil {
+			return position
+		}
 	}
+	return definition.Positions[field]
 }
@@ -0,0 +1,38 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/jbrunton/gflows/config"
+	"github.com/jbrunton/gflows/io/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetContent(t *testing.T) {
+	definition := &Definition{Destination: ".github/workflows/test.yml"}
+
+	definition.SetContent("jobs:\n  test:\n    runs-on: ubuntu-latest\n", &pkg.PathInfo{Description: "test.jsonnet"}, ContentOptions{})
+
+	assert.Equal(t, "# File generated by gflows, do not modify\n# Source: test.jsonnet\njobs:\n  test:\n    runs-on: ubuntu-latest\n", definition.Content)
+	assert.Equal(t, &SourcePosition{File: ".github/workflows/test.yml", Line: 4, Col: 3}, definition.Positions["jobs.test"])
+	assert.Equal(t, ".github/workflows/test.yml:5:5", definition.FindPosition("jobs.test", "runs-on").String())
+	assert.Equal(t, "jobs:\n  test:\n    runs-on: ubuntu-latest\n", definition.Body())
+}
+
+func TestSetContentInvalidYaml(t *testing.T) {
+	definition := &Definition{Destination: ".github/workflows/test.yml"}
+
+	definition.SetContent("jobs:\n  test: foo: bar\n", &pkg.PathInfo{Description: "test.jsonnet"}, ContentOptions{})
+
+	assert.False(t, definition.Status.Valid)
+	assert.Equal(t, []string{".github/workflows/test.yml:4: yaml: line 4: mapping values are not allowed in this context"}, definition.Status.Errors)
+}
+
+func TestSetContentWithGitHubKeyOrder(t *testing.T) {
+	definition := &Definition{Destination: ".github/workflows/test.yml"}
+
+	definition.SetContent("jobs:\n  test:\n    runs-on: ubuntu-latest\nname: test\n", &pkg.PathInfo{Description: "test.jsonnet"}, ContentOptions{KeyOrder: config.KeyOrderGitHub})
+
+	assert.Equal(t, "# File generated by gflows, do not modify\n# Source: test.jsonnet\nname: test\njobs:\n  test:\n    runs-on: ubuntu-latest\n", definition.Content)
+	assert.Equal(t, &SourcePosition{File: ".github/workflows/test.yml", Line: 3, Col: 1}, definition.Positions["name"])
+}
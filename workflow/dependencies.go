@@ -0,0 +1,24 @@
+package workflow
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// DependencySet - the files read while generating a workflow
+type DependencySet map[string]bool
+
+// Add - adds the path to the set
+func (deps DependencySet) Add(path string) {
+	deps[filepath.Clean(path)] = true
+}
+
+// Paths - returns the paths in the set, sorted
+func (deps DependencySet) Paths() []string {
+	paths := []string{}
+	for path := range deps {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
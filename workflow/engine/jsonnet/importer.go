@@ -0,0 +1,102 @@
+package jsonnet
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	gojsonnet "github.com/google/go-jsonnet"
+	"github.com/spf13/afero"
+)
+
+// FsImporter - imports jsonnet files from an afero filesystem. This works as gojsonnet.FileImporter
+// does, but reads from the given fs rather than the OS filesystem (so that templates can be
+// evaluated from memory, e.g. when reading a git revision).
+type FsImporter struct {
+	fs     *afero.Afero
+	jpaths []string
+	cache  map[string]*fsCacheEntry
+}
+
+type fsCacheEntry struct {
+	exists   bool
+	contents gojsonnet.Contents
+}
+
+// NewFsImporter - returns a new FsImporter which searches the given library paths
+func NewFsImporter(fs *afero.Afero, jpaths []string) *FsImporter {
+	return &FsImporter{
+		fs:     fs,
+		jpaths: jpaths,
+		cache:  make(map[string]*fsCacheEntry),
+	}
+}
+
+// Import - imports the given path, relative to the importing file or else one of the library paths
+func (importer *FsImporter) Import(importedFrom, importedPath string) (gojsonnet.Contents, string, error) {
+	dir, _ := path.Split(importedFrom)
+	found, contents, foundAt, err := importer.tryPath(dir, importedPath)
+	if err != nil {
+		return gojsonnet.Contents{}, "", err
+	}
+
+	for i := len(importer.jpaths) - 1; !found && i >= 0; i-- {
+		found, contents, foundAt, err = importer.tryPath(importer.jpaths[i], importedPath)
+		if err != nil {
+			return gojsonnet.Contents{}, "", err
+		}
+	}
+
+	if !found {
+		return gojsonnet.Contents{}, "", fmt.Errorf("couldn't open import %#v: no match locally or in the Jsonnet library paths", importedPath)
+	}
+	return contents, foundAt, nil
+}
+
+func (importer *FsImporter) tryPath(dir string, importedPath string) (bool, gojsonnet.Contents, string, error) {
+	var fullPath string
+	if path.IsAbs(importedPath) {
+		fullPath = importedPath
+	} else {
+		fullPath = path.Join(dir, importedPath)
+	}
+
+	entry, isCached := importer.cache[fullPath]
+	if !isCached {
+		data, err := importer.fs.ReadFile(fullPath)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				return false, gojsonnet.Contents{}, "", err
+			}
+			entry = &fsCacheEntry{exists: false}
+		} else {
+			entry = &fsCacheEntry{exists: true, contents: gojsonnet.MakeContents(string(data))}
+		}
+		importer.cache[fullPath] = entry
+	}
+	return entry.exists, entry.contents, fullPath, nil
+}
+
+// TrackingImporter - wraps an importer to record the path of each file successfully imported
+// (including by importstr and importbin)
+type TrackingImporter struct {
+	importer gojsonnet.Importer
+	onImport func(foundAt string)
+}
+
+// NewTrackingImporter - returns a TrackingImporter which invokes onImport for each import
+func NewTrackingImporter(importer gojsonnet.Importer, onImport func(foundAt string)) *TrackingImporter {
+	return &TrackingImporter{
+		importer: importer,
+		onImport: onImport,
+	}
+}
+
+// Import - imports the path using the wrapped importer, recording it if found
+func (importer *TrackingImporter) Import(importedFrom, importedPath string) (gojsonnet.Contents, string, error) {
+	contents, foundAt, err := importer.importer.Import(importedFrom, importedPath)
+	if err == nil {
+		importer.onImport(foundAt)
+	}
+	return contents, foundAt, err
+}
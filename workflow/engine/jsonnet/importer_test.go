@@ -0,0 +1,48 @@
+package jsonnet
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFsImporter(t *testing.T) {
+	fs := &afero.Afero{Fs: afero.NewMemMapFs()}
+	fs.WriteFile(".gflows/workflows/common.libsonnet", []byte("{ local: true }"), 0644)
+	fs.WriteFile(".gflows/libs/common.libsonnet", []byte("{ local: false }"), 0644)
+	fs.WriteFile(".gflows/libs/steps.libsonnet", []byte("{ steps: true }"), 0644)
+	importer := NewFsImporter(fs, []string{".gflows/libs"})
+
+	contents, foundAt, err := importer.Import(".gflows/workflows/test.jsonnet", "common.libsonnet")
+	assert.NoError(t, err)
+	assert.Equal(t, ".gflows/workflows/common.libsonnet", foundAt)
+	assert.Equal(t, "{ local: true }", contents.String())
+
+	contents, foundAt, err = importer.Import(".gflows/workflows/test.jsonnet", "steps.libsonnet")
+	assert.NoError(t, err)
+	assert.Equal(t, ".gflows/libs/steps.libsonnet", foundAt)
+	assert.Equal(t, "{ steps: true }", contents.String())
+
+	_, _, err = importer.Import(".gflows/workflows/test.jsonnet", "missing.libsonnet")
+	assert.EqualError(t, err, `couldn't open import "missing.libsonnet": no match locally or in the Jsonnet library paths`)
+}
+
+func TestTrackingImporter(t *testing.T) {
+	fs := &afero.Afero{Fs: afero.NewMemMapFs()}
+	fs.WriteFile(".gflows/libs/steps.libsonnet", []byte("{ steps: true }"), 0644)
+	fs.WriteFile(".gflows/scripts/build.sh", []byte("make build"), 0644)
+	imports := []string{}
+	importer := NewTrackingImporter(NewFsImporter(fs, []string{".gflows/libs"}), func(foundAt string) {
+		imports = append(imports, foundAt)
+	})
+
+	_, _, err := importer.Import(".gflows/workflows/test.jsonnet", "steps.libsonnet")
+	assert.NoError(t, err)
+	_, _, err = importer.Import(".gflows/workflows/test.jsonnet", "../scripts/build.sh")
+	assert.NoError(t, err)
+	_, _, err = importer.Import(".gflows/workflows/test.jsonnet", "missing.libsonnet")
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{".gflows/libs/steps.libsonnet", ".gflows/scripts/build.sh"}, imports)
+}
@@ -23,106 +23,109 @@ type JsonnetTemplateEngine struct {
 	context       *config.GFlowsContext
 	contentWriter *content.Writer
 	env           *env.GFlowsEnv
+	cache         *workflow.BuildCache
 }
 
-func NewJsonnetTemplateEngine(fs *afero.Afero, context *config.GFlowsContext, contentWriter *content.Writer, env *env.GFlowsEnv) *JsonnetTemplateEngine {
+func NewJsonnetTemplateEngine(fs *afero.Afero, context *config.GFlowsContext, contentWriter *content.Writer, env *env.GFlowsEnv, cache *workflow.BuildCache) *JsonnetTemplateEngine {
 	return &JsonnetTemplateEngine{
 		fs:            fs,
 		context:       context,
 		contentWriter: contentWriter,
 		env:           env,
+		cache:         cache,
 	}
 }
 
-func (engine *JsonnetTemplateEngine) GetObservableSources() ([]string, error) {
-	files := []string{}
-	for _, libPath := range append(
-		engine.context.Config.GetAllLibs(),
-		engine.context.WorkflowsDir(),
-		engine.context.LibsDir(),
-	) {
-		libInfo, err := pkg.GetLibInfo(libPath, engine.fs)
-		if err != nil {
-			return nil, err
-		}
-
-		if libInfo.IsRemote || !libInfo.Exists {
-			// Can't watch remote or non-existent files, so continue
-			continue
-		}
-
-		// If it's a file...
-		if !libInfo.IsDir {
-			// ...add it to the list
-			files = append(files, libPath)
-			continue
-		}
-
-		// If we reach here, then libPath is a directory, so walk it
-		err = engine.fs.Walk(libPath, func(path string, f os.FileInfo, err error) error {
-			ext := filepath.Ext(path)
-			// TODO: should probably include other files, since jsonnet can include json (and maybe text? any other types?)
-			if ext == ".jsonnet" || ext == ".libsonnet" {
-				files = append(files, path)
-			}
-			return nil
-		})
-
-		if err != nil {
-			return nil, err
+// GetObservableSources - returns the workflow templates and the files the given definitions
+// import (as recorded when evaluating them)
+func (engine *JsonnetTemplateEngine) GetObservableSources(definitions []*workflow.Definition) ([]string, error) {
+	templates, err := engine.getWorkflowTemplates()
+	if err != nil {
+		return nil, err
+	}
+	sources := workflow.DependencySet{}
+	for _, template := range templates {
+		sources.Add(template.LocalPath)
+	}
+	for _, definition := range definitions {
+		for _, dependency := range definition.Dependencies {
+			sources.Add(dependency)
 		}
 	}
-
-	return files, nil
+	return sources.Paths(), nil
 }
 
 // GetWorkflowDefinitions - get workflow definitions for the given context
 func (engine *JsonnetTemplateEngine) GetWorkflowDefinitions() ([]*workflow.Definition, error) {
+	return engine.GetWorkflowDefinitionsMatching(nil)
+}
+
+// GetWorkflowDefinitionsMatching - get workflow definitions for the templates selected by the filter
+func (engine *JsonnetTemplateEngine) GetWorkflowDefinitionsMatching(filter *workflow.WorkflowFilter) ([]*workflow.Definition, error) {
 	templates, err := engine.getWorkflowTemplates()
 	if err != nil {
 		return nil, err
 	}
-	definitions := []*workflow.Definition{}
-	for _, template := range templates {
+	return workflow.GetDefinitionsInParallel(templates, engine.context.Jobs, func(template *pkg.PathInfo) (*workflow.Definition, error) {
 		workflowName := engine.getWorkflowName(template.LocalPath)
-		vm, err := engine.createVM(workflowName)
-		if err != nil {
-			return []*workflow.Definition{}, err
-		}
-		input, err := engine.fs.ReadFile(template.LocalPath)
-		if err != nil {
-			return []*workflow.Definition{}, err
+		if !filter.Matches(workflowName) {
+			return nil, nil
 		}
+		return engine.getWorkflowDefinition(workflowName, template)
+	})
+}
 
-		destinationPath := filepath.Join(engine.context.GitHubDir, "workflows/", workflowName+".yml")
-		definition := &workflow.Definition{
-			Name:        workflowName,
-			Source:      template.LocalPath,
-			Description: template.Description,
-			Destination: destinationPath,
-			Status:      workflow.ValidationResult{Valid: true},
-		}
+func (engine *JsonnetTemplateEngine) getWorkflowDefinition(workflowName string, template *pkg.PathInfo) (*workflow.Definition, error) {
+	jpaths, err := engine.env.GetLibPaths(workflowName)
+	if err != nil {
+		return nil, err
+	}
+	cacheKey, err := engine.cache.DefinitionKey("jsonnet", workflowName, template, append([]string{filepath.Dir(template.LocalPath)}, jpaths...))
+	if err != nil {
+		return nil, err
+	}
+	if definition := engine.cache.GetDefinition(cacheKey); definition != nil {
+		return definition, nil
+	}
 
-		workflow, err := vm.EvaluateSnippet(template.LocalPath, string(input))
+	dependencies := workflow.DependencySet{}
+	dependencies.Add(template.LocalPath)
+	vm := engine.createVM(jpaths, dependencies)
+	input, err := engine.fs.ReadFile(template.LocalPath)
+	if err != nil {
+		return nil, err
+	}
 
-		if err != nil {
-			definition.Status.Valid = false
-			errorDescription := strings.Trim(err.Error(), " \n\r")
-			if strings.Contains(err.Error(), "expected string result") {
-				errorDescription = strings.Join([]string{
-					errorDescription,
-					"You probably need to serialize the output to YAML. See https://github.com/jbrunton/gflows/wiki/Templates#serialization",
-				}, "\n")
-			}
-			definition.Status.Errors = []string{errorDescription}
-		} else {
-			definition.SetContent(workflow, template)
-		}
+	destinationPath := filepath.Join(engine.context.GitHubDir, "workflows/", workflowName+".yml")
+	definition := &workflow.Definition{
+		Name:        workflowName,
+		Source:      template.LocalPath,
+		Description: template.Description,
+		Commit:      template.Commit,
+		Destination: destinationPath,
+		Status:      workflow.ValidationResult{Valid: true},
+	}
+
+	contentOptions := workflow.NewContentOptions(engine.context, workflowName)
+	workflow, err := vm.EvaluateSnippet(template.LocalPath, string(input))
 
-		definitions = append(definitions, definition)
+	if err != nil {
+		definition.Status.Valid = false
+		errorDescription := strings.Trim(err.Error(), " \n\r")
+		if strings.Contains(err.Error(), "expected string result") {
+			errorDescription = strings.Join([]string{
+				errorDescription,
+				"You probably need to serialize the output to YAML. See https://github.com/jbrunton/gflows/wiki/Templates#serialization",
+			}, "\n")
+		}
+		definition.Status.Errors = []string{errorDescription}
+	} else {
+		definition.SetContent(workflow, template, contentOptions)
 	}
+	definition.Dependencies = dependencies.Paths()
+	engine.cache.PutDefinition(cacheKey, definition)
 
-	return definitions, nil
+	return definition, nil
 }
 
 func (engine *JsonnetTemplateEngine) ImportWorkflow(wf *workflow.GitHubWorkflow) (string, error) {
@@ -200,15 +203,11 @@ func (engine *JsonnetTemplateEngine) getWorkflowName(filename string) string {
 	return strings.TrimSuffix(templateFileName, filepath.Ext(templateFileName))
 }
 
-func (engine *JsonnetTemplateEngine) createVM(workflowName string) (*gojsonnet.VM, error) {
+// createVM - returns a VM which imports from the given library paths, and records imported files in
+// dependencies
+func (engine *JsonnetTemplateEngine) createVM(jpaths []string, dependencies workflow.DependencySet) *gojsonnet.VM {
 	vm := gojsonnet.MakeVM()
-	jpaths, err := engine.env.GetLibPaths(workflowName)
-	if err != nil {
-		return nil, err
-	}
-	vm.Importer(&gojsonnet.FileImporter{
-		JPaths: jpaths,
-	})
+	vm.Importer(jsonnet.NewTrackingImporter(jsonnet.NewFsImporter(engine.fs, jpaths), dependencies.Add))
 	vm.StringOutput = true
-	return vm, nil
+	return vm
 }
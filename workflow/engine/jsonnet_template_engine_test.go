@@ -27,7 +27,8 @@ func newJsonnetTemplateEngine(config string, roundTripper http.RoundTripper) (*c
 	repoManager := content.NewRepoManager(container.GitAdapter(), container.FileSystem(), container.Logger())
 	installer := env.NewGFlowsLibInstaller(container.FileSystem(), container.ContentReader(), container.ContentWriter(), container.Logger(), repoManager)
 	env := env.NewGFlowsEnv(container.FileSystem(), installer, context, container.Logger())
-	templateEngine := NewJsonnetTemplateEngine(container.FileSystem(), context, container.ContentWriter(), env)
+	buildCache := workflow.NewBuildCache(container.FileSystem(), container.Logger(), context)
+	templateEngine := NewJsonnetTemplateEngine(container.FileSystem(), context, container.ContentWriter(), env, buildCache)
 	return container, context, templateEngine
 }
 
@@ -41,17 +42,34 @@ func TestGetJsonnetWorkflowDefinitions(t *testing.T) {
 	expectedContent := fixtures.ExampleWorkflow("test.jsonnet")
 	expectedJson, _ := yamlutil.YamlToJson(expectedContent)
 	expectedDefinition := workflow.Definition{
-		Name:        "test",
-		Source:      ".gflows/workflows/test.jsonnet",
-		Description: ".gflows/workflows/test.jsonnet",
-		Destination: ".github/workflows/test.yml",
-		Content:     expectedContent,
-		Status:      workflow.ValidationResult{Valid: true},
-		JSON:        expectedJson,
+		Name:         "test",
+		Source:       ".gflows/workflows/test.jsonnet",
+		Description:  ".gflows/workflows/test.jsonnet",
+		Destination:  ".github/workflows/test.yml",
+		Content:      expectedContent,
+		Header:       "# File generated by gflows, do not modify\n# Source: .gflows/workflows/test.jsonnet\n",
+		Dependencies: []string{".gflows/workflows/test.jsonnet"},
+		Status:       workflow.ValidationResult{Valid: true},
+		JSON:         expectedJson,
+		Positions:    definitions[0].Positions,
 	}
 	assert.Equal(t, []*workflow.Definition{&expectedDefinition}, definitions)
 }
 
+func TestGetJsonnetWorkflowDefinitionsMatching(t *testing.T) {
+	container, _, templateEngine := newJsonnetTemplateEngine("", fixtures.NewMockRoundTripper())
+	fs := container.FileSystem()
+	fs.WriteFile(".gflows/workflows/test.jsonnet", []byte(fixtures.ExampleJsonnetTemplate), 0644)
+	fs.WriteFile(".gflows/workflows/other.jsonnet", []byte(fixtures.ExampleJsonnetTemplate), 0644)
+
+	filter, _ := workflow.NewWorkflowFilter([]string{"test"}, nil)
+	definitions, err := templateEngine.GetWorkflowDefinitionsMatching(filter)
+
+	assert.NoError(t, err)
+	assert.Len(t, definitions, 1)
+	assert.Equal(t, "test", definitions[0].Name)
+}
+
 func TestGetJsonnetWorkflowDefinitionsWithLibs(t *testing.T) {
 	container, _, templateEngine := newJsonnetTemplateEngine("", fixtures.NewMockRoundTripper())
 	fs := container.FileSystem()
@@ -65,22 +83,28 @@ func TestGetJsonnetWorkflowDefinitionsWithLibs(t *testing.T) {
 	expectedLocalContent := fixtures.ExampleWorkflow("test.jsonnet")
 	expectedLocalJson, _ := yamlutil.YamlToJson(expectedLocalContent)
 	expectedLocalDefinition := workflow.Definition{
-		Name:        "test",
-		Source:      ".gflows/workflows/test.jsonnet",
-		Description: ".gflows/workflows/test.jsonnet",
-		Destination: ".github/workflows/test.yml",
-		Content:     expectedLocalContent,
-		Status:      workflow.ValidationResult{Valid: true},
-		JSON:        expectedLocalJson,
+		Name:         "test",
+		Source:       ".gflows/workflows/test.jsonnet",
+		Description:  ".gflows/workflows/test.jsonnet",
+		Destination:  ".github/workflows/test.yml",
+		Content:      expectedLocalContent,
+		Header:       "# File generated by gflows, do not modify\n# Source: .gflows/workflows/test.jsonnet\n",
+		Dependencies: []string{".gflows/workflows/test.jsonnet"},
+		Status:       workflow.ValidationResult{Valid: true},
+		JSON:         expectedLocalJson,
+		Positions:    definitions[1].Positions,
 	}
 	expectedRemoteDefinition := workflow.Definition{
-		Name:        "lib-workflow",
-		Source:      filepath.Join(lib.LocalDir, "workflows/lib-workflow.jsonnet"),
-		Description: "my-lib/workflows/lib-workflow.jsonnet",
-		Destination: ".github/workflows/lib-workflow.yml",
-		Content:     "# File generated by gflows, do not modify\n# Source: my-lib/workflows/lib-workflow.jsonnet\n{}\n",
-		Status:      workflow.ValidationResult{Valid: true},
-		JSON:        make(map[string]interface{}),
+		Name:         "lib-workflow",
+		Source:       filepath.Join(lib.LocalDir, "workflows/lib-workflow.jsonnet"),
+		Description:  "my-lib/workflows/lib-workflow.jsonnet",
+		Destination:  ".github/workflows/lib-workflow.yml",
+		Content:      "# File generated by gflows, do not modify\n# Source: my-lib/workflows/lib-workflow.jsonnet\n{}\n",
+		Header:       "# File generated by gflows, do not modify\n# Source: my-lib/workflows/lib-workflow.jsonnet\n",
+		Dependencies: []string{filepath.Join(lib.LocalDir, "workflows/lib-workflow.jsonnet")},
+		Status:       workflow.ValidationResult{Valid: true},
+		JSON:         make(map[string]interface{}),
+		Positions:    make(map[string]*workflow.SourcePosition),
 	}
 	assert.Equal(t, []*workflow.Definition{&expectedRemoteDefinition, &expectedLocalDefinition}, definitions)
 }
@@ -98,11 +122,12 @@ func TestSerializationError(t *testing.T) {
 		"You probably need to serialize the output to YAML. See https://github.com/jbrunton/gflows/wiki/Templates#serialization",
 	}, "\n")
 	expectedDefinition := workflow.Definition{
-		Name:        "test",
-		Source:      ".gflows/workflows/test.jsonnet",
-		Description: ".gflows/workflows/test.jsonnet",
-		Destination: ".github/workflows/test.yml",
-		Content:     "",
+		Name:         "test",
+		Source:       ".gflows/workflows/test.jsonnet",
+		Description:  ".gflows/workflows/test.jsonnet",
+		Destination:  ".github/workflows/test.yml",
+		Content:      "",
+		Dependencies: []string{".gflows/workflows/test.jsonnet"},
 		Status: workflow.ValidationResult{
 			Valid:  false,
 			Errors: []string{expectedError},
@@ -118,29 +143,42 @@ func TestGetJsonnetObservableSources(t *testing.T) {
 		"  engine: jsonnet",
 		"  defaults:",
 		"    libs:",
-		"    - vendor",
-		"    - foo/bar.libsonnet",
+		"    - ../vendor",
 		"    - https://example.com/config.yml",
 	}, "\n")
 	container, _, templateEngine := newJsonnetTemplateEngine(config, fixtures.NewMockRoundTripper())
 	fs := container.FileSystem()
-	fs.WriteFile(".gflows/workflows/test.jsonnet", []byte(fixtures.ExampleJsonnetTemplate), 0644)
-	fs.WriteFile(".gflows/workflows/test.libsonnet", []byte(fixtures.ExampleJsonnetTemplate), 0644)
-	fs.WriteFile(".gflows/workflows/invalid.ext", []byte(fixtures.ExampleJsonnetTemplate), 0644)
-	fs.WriteFile(".gflows/libs/lib.libsonnet", []byte(fixtures.ExampleJsonnetTemplate), 0644)
-	fs.WriteFile("vendor/lib.libsonnet", []byte(fixtures.ExampleJsonnetTemplate), 0644)
-	fs.WriteFile("foo/bar.libsonnet", []byte(fixtures.ExampleJsonnetTemplate), 0644)
+	fs.WriteFile(".gflows/workflows/test.jsonnet", []byte(strings.Join([]string{
+		"local lib = import 'lib.libsonnet';",
+		"local vendor = import 'vendor.libsonnet';",
+		"local script = importstr '../scripts/build.sh';",
+		"local unused = import 'unused.libsonnet';",
+		"std.manifestYamlDoc(lib + vendor + { run: script })",
+	}, "\n")), 0644)
+	fs.WriteFile(".gflows/workflows/other.jsonnet", []byte("import 'missing.libsonnet'"), 0644)
+	fs.WriteFile(".gflows/workflows/test.libsonnet", []byte("{}"), 0644)
+	fs.WriteFile(".gflows/libs/lib.libsonnet", []byte("{}"), 0644)
+	// jsonnet is lazy, so unused imports aren't dependencies
+	fs.WriteFile(".gflows/libs/unused.libsonnet", []byte("{}"), 0644)
+	fs.WriteFile(".gflows/scripts/build.sh", []byte("make build"), 0644)
+	fs.WriteFile("vendor/vendor.libsonnet", []byte("{}"), 0644)
 
-	sources, err := templateEngine.GetObservableSources()
+	definitions, _ := templateEngine.GetWorkflowDefinitions()
+	sources, err := templateEngine.GetObservableSources(definitions)
 
 	assert.NoError(t, err)
 	assert.Equal(t, []string{
-		"vendor/lib.libsonnet",
-		"foo/bar.libsonnet",
-		".gflows/workflows/test.jsonnet",
-		".gflows/workflows/test.libsonnet",
 		".gflows/libs/lib.libsonnet",
+		".gflows/scripts/build.sh",
+		".gflows/workflows/other.jsonnet",
+		".gflows/workflows/test.jsonnet",
+		"vendor/vendor.libsonnet",
 	}, sources)
+
+	// without definitions, only the templates are known
+	sources, err = templateEngine.GetObservableSources(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{".gflows/workflows/other.jsonnet", ".gflows/workflows/test.jsonnet"}, sources)
 }
 
 func TestGetJsonnetWorkflowTemplates(t *testing.T) {
@@ -168,3 +206,28 @@ func TestGetJsonnetWorkflowName(t *testing.T) {
 	assert.Equal(t, "my-workflow-1", templateEngine.getWorkflowName("/workflows/my-workflow-1.jsonnet"))
 	assert.Equal(t, "my-workflow-2", templateEngine.getWorkflowName("/workflows/workflows/my-workflow-2.jsonnet"))
 }
+
+func TestJsonnetWorkflowDefinitionsCache(t *testing.T) {
+	container, _, templateEngine := newJsonnetTemplateEngine("", fixtures.NewMockRoundTripper())
+	fs := container.FileSystem()
+	fs.WriteFile(".gflows/workflows/test.jsonnet", []byte("std.manifestYamlDoc(import 'workflow.libsonnet')"), 0644)
+	fs.WriteFile(".gflows/libs/workflow.libsonnet", []byte("{ 'on': 'push' }"), 0644)
+
+	definitions, err := templateEngine.GetWorkflowDefinitions()
+	assert.NoError(t, err)
+	cachedDefinitions, err := templateEngine.GetWorkflowDefinitions()
+	assert.NoError(t, err)
+	assert.Equal(t, definitions, cachedDefinitions)
+
+	// changes to imported files invalidate the cache
+	fs.WriteFile(".gflows/libs/workflow.libsonnet", []byte("{ 'on': 'pull_request' }"), 0644)
+	definitions, err = templateEngine.GetWorkflowDefinitions()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"on": "pull_request"}, definitions[0].JSON)
+
+	// as do new files which might change how imports resolve
+	fs.WriteFile(".gflows/workflows/workflow.libsonnet", []byte("{ 'on': 'release' }"), 0644)
+	definitions, err = templateEngine.GetWorkflowDefinitions()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"on": "release"}, definitions[0].JSON)
+}
@@ -10,12 +10,21 @@ import (
 )
 
 type FileSource struct {
-	fs   *afero.Afero
-	path string
-	dir  string
+	fs     *afero.Afero
+	path   string
+	dir    string
+	onRead func(path string)
 }
 
-func NewFileSource(fs *afero.Afero, path, dir string) FileSource { return FileSource{fs, path, dir} }
+func NewFileSource(fs *afero.Afero, path, dir string) FileSource {
+	return FileSource{fs: fs, path: path, dir: dir}
+}
+
+// NewTrackedFileSource - returns a FileSource which invokes onRead when ytt reads the file, so that
+// the files actually loaded (e.g. libs which are loaded by templates) can be recorded
+func NewTrackedFileSource(fs *afero.Afero, path, dir string, onRead func(path string)) FileSource {
+	return FileSource{fs: fs, path: path, dir: dir, onRead: onRead}
+}
 
 func (s FileSource) Description() string { return fmt.Sprintf("file '%s'", s.path) }
 
@@ -43,4 +52,9 @@ func (s FileSource) RelativePath() (string, error) {
 	return "", fmt.Errorf("unknown relative path for %s", s.path)
 }
 
-func (s FileSource) Bytes() ([]byte, error) { return s.fs.ReadFile(s.path) }
+func (s FileSource) Bytes() ([]byte, error) {
+	if s.onRead != nil {
+		s.onRead(s.path)
+	}
+	return s.fs.ReadFile(s.path)
+}
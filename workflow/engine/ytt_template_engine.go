@@ -1,8 +1,11 @@
 package engine
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/davecgh/go-spew/spew"
@@ -18,9 +21,11 @@ import (
 	"github.com/jbrunton/gflows/yamlutil"
 	cmdcore "github.com/k14s/ytt/pkg/cmd/core"
 	cmdtpl "github.com/k14s/ytt/pkg/cmd/template"
+	"github.com/k14s/ytt/pkg/filepos"
 	"github.com/k14s/ytt/pkg/files"
 	"github.com/k14s/ytt/pkg/schema"
 	"github.com/k14s/ytt/pkg/workspace"
+	"github.com/k14s/ytt/pkg/yamlmeta"
 	"github.com/spf13/afero"
 	"github.com/thoas/go-funk"
 )
@@ -31,46 +36,39 @@ type YttTemplateEngine struct {
 	contentWriter *content.Writer
 	env           *env.GFlowsEnv
 	logger        *io.Logger
+	cache         *workflow.BuildCache
 }
 
-func NewYttTemplateEngine(fs *afero.Afero, context *config.GFlowsContext, contentWriter *content.Writer, env *env.GFlowsEnv, logger *io.Logger) *YttTemplateEngine {
+func NewYttTemplateEngine(fs *afero.Afero, context *config.GFlowsContext, contentWriter *content.Writer, env *env.GFlowsEnv, logger *io.Logger, cache *workflow.BuildCache) *YttTemplateEngine {
 	return &YttTemplateEngine{
 		fs:            fs,
 		context:       context,
 		contentWriter: contentWriter,
 		env:           env,
 		logger:        logger,
+		cache:         cache,
 	}
 }
 
-func (engine *YttTemplateEngine) GetObservableSources() ([]string, error) {
-	files := []string{}
-	for _, libPath := range append(
-		engine.context.Config.GetAllLibs(),
-		engine.context.WorkflowsDir(),
-		engine.context.LibsDir(),
-	) {
-		libInfo, err := pkg.GetLibInfo(libPath, engine.fs)
-		if err != nil {
-			return nil, err
-		}
-
-		if libInfo.IsRemote || !libInfo.Exists {
-			// Can't watch remote or non-existent files, so continue
-			continue
+// GetObservableSources - returns the files in the workflow template directories and the files ytt
+// loaded from libs for the given definitions (as recorded when evaluating them)
+func (engine *YttTemplateEngine) GetObservableSources(definitions []*workflow.Definition) ([]string, error) {
+	templates, err := engine.getWorkflowTemplates()
+	if err != nil {
+		return nil, err
+	}
+	sources := workflow.DependencySet{}
+	for _, template := range templates {
+		for _, path := range engine.getSourcesInDir(template.LocalPath) {
+			sources.Add(path)
 		}
-
-		// If it's a file...
-		if !libInfo.IsDir {
-			// ...add it to the list
-			files = append(files, libPath)
-			continue
+	}
+	for _, definition := range definitions {
+		for _, dependency := range definition.Dependencies {
+			sources.Add(dependency)
 		}
-
-		// If we reach here, it's a directory
-		files = append(files, engine.getSourcesInDir(libPath)...)
 	}
-	return files, nil
+	return sources.Paths(), nil
 }
 
 func (engine *YttTemplateEngine) getWorkflowTemplates() ([]*pkg.PathInfo, error) {
@@ -108,35 +106,67 @@ func (engine *YttTemplateEngine) getWorkflowTemplates() ([]*pkg.PathInfo, error)
 
 // GetWorkflowDefinitions - get workflow definitions for the given context
 func (engine *YttTemplateEngine) GetWorkflowDefinitions() ([]*workflow.Definition, error) {
+	return engine.GetWorkflowDefinitionsMatching(nil)
+}
+
+// GetWorkflowDefinitionsMatching - get workflow definitions for the templates selected by the filter
+func (engine *YttTemplateEngine) GetWorkflowDefinitionsMatching(filter *workflow.WorkflowFilter) ([]*workflow.Definition, error) {
 	templates, err := engine.getWorkflowTemplates()
 	if err != nil {
 		return nil, err
 	}
-	definitions := []*workflow.Definition{}
-	for _, template := range templates {
+	return workflow.GetDefinitionsInParallel(templates, engine.context.Jobs, func(template *pkg.PathInfo) (*workflow.Definition, error) {
 		workflowName := filepath.Base(template.LocalPath)
-		destinationPath := filepath.Join(engine.context.GitHubDir, "workflows/", workflowName+".yml")
-		definition := &workflow.Definition{
-			Name:        workflowName,
-			Source:      template.LocalPath,
-			Description: template.Description,
-			Destination: destinationPath,
-			Status:      workflow.ValidationResult{Valid: true},
+		if !filter.Matches(workflowName) {
+			return nil, nil
 		}
+		return engine.getWorkflowDefinition(workflowName, template)
+	})
+}
 
-		workflow, err := engine.apply(workflowName, template.LocalPath)
+func (engine *YttTemplateEngine) getWorkflowDefinition(workflowName string, template *pkg.PathInfo) (*workflow.Definition, error) {
+	libPaths, err := engine.env.GetLibPaths(workflowName)
+	if err != nil {
+		return nil, err
+	}
+	cacheKey, err := engine.cache.DefinitionKey("ytt", workflowName, template, append([]string{template.LocalPath}, libPaths...))
+	if err != nil {
+		return nil, err
+	}
+	if definition := engine.cache.GetDefinition(cacheKey); definition != nil {
+		return definition, nil
+	}
 
-		if err != nil {
-			definition.Status.Valid = false
-			definition.Status.Errors = []string{strings.Trim(err.Error(), " \n\r")}
-		} else {
-			definition.SetContent(workflow, template)
-		}
+	destinationPath := filepath.Join(engine.context.GitHubDir, "workflows/", workflowName+".yml")
+	definition := &workflow.Definition{
+		Name:        workflowName,
+		Source:      template.LocalPath,
+		Description: template.Description,
+		Commit:      template.Commit,
+		Destination: destinationPath,
+		Status:      workflow.ValidationResult{Valid: true},
+	}
+
+	contentOptions := workflow.NewContentOptions(engine.context, workflowName)
+	dependencies := workflow.DependencySet{}
+	workflow, positions, err := engine.apply(workflowName, template.LocalPath, dependencies)
 
-		definitions = append(definitions, definition)
+	if err != nil {
+		definition.Status.Valid = false
+		definition.Status.Errors = []string{strings.Trim(err.Error(), " \n\r")}
+	} else {
+		definition.SetContent(workflow, template, contentOptions)
+		if definition.Status.Valid {
+			// prefer positions in the templates to positions in the generated content
+			for path, position := range positions {
+				definition.Positions[path] = position
+			}
+		}
 	}
+	definition.Dependencies = dependencies.Paths()
+	engine.cache.PutDefinition(cacheKey, definition)
 
-	return definitions, nil
+	return definition, nil
 }
 
 func (engine *YttTemplateEngine) ImportWorkflow(workflow *workflow.GitHubWorkflow) (string, error) {
@@ -189,10 +219,12 @@ func (engine *YttTemplateEngine) getSourcesInDir(dir string) []string {
 	return files
 }
 
-func (engine *YttTemplateEngine) getInput(workflowName string, templateDir string) (*cmdtpl.TemplateInput, error) {
+// getInput - returns the template and lib files for the workflow. Reads are recorded in
+// dependencies, so that only the files ytt actually loads are recorded.
+func (engine *YttTemplateEngine) getInput(workflowName string, templateDir string, dependencies workflow.DependencySet) (*cmdtpl.TemplateInput, error) {
 	var in cmdtpl.TemplateInput
 	for _, sourcePath := range engine.getSourcesInDir(templateDir) {
-		source := ytt.NewFileSource(engine.fs, sourcePath, filepath.Dir(sourcePath))
+		source := ytt.NewTrackedFileSource(engine.fs, sourcePath, filepath.Dir(sourcePath), dependencies.Add)
 		file, err := files.NewFileFromSource(source)
 		if err != nil {
 			panic(err)
@@ -200,8 +232,8 @@ func (engine *YttTemplateEngine) getInput(workflowName string, templateDir strin
 		in.Files = append(in.Files, file)
 	}
 	candidatePaths, err := engine.env.GetLibPaths(workflowName)
-	// NewSortedFilesFromPaths errors if a path doesn't exist. Since GetLibPaths returns a libs
-	// directory for all packages (regardless of whether one exists), we need to filter here.
+	// GetLibPaths returns a libs directory for all packages (regardless of whether one exists), so
+	// we need to filter here.
 	paths := funk.Filter(candidatePaths, func(path string) bool {
 		exists, err := engine.fs.Exists(path)
 		if err != nil {
@@ -213,19 +245,59 @@ func (engine *YttTemplateEngine) getInput(workflowName string, templateDir strin
 	if err != nil {
 		return nil, err
 	}
-	libs, err := files.NewSortedFilesFromPaths(paths, files.SymlinkAllowOpts{})
+	for _, path := range paths {
+		libs, err := engine.getLibFiles(path, dependencies)
+		if err != nil {
+			return nil, err
+		}
+		in.Files = append(in.Files, libs...)
+	}
+	in.Files = files.NewSortedFiles(in.Files)
+	return &in, nil
+}
+
+// getLibFiles - returns the files for the given lib path (either a file or a directory), sorted
+// by relative path. This works as files.NewSortedFilesFromPaths does for each path, but reads
+// from engine.fs.
+func (engine *YttTemplateEngine) getLibFiles(path string, dependencies workflow.DependencySet) ([]*files.File, error) {
+	isDir, err := engine.fs.IsDir(path)
 	if err != nil {
 		return nil, err
 	}
-	in.Files = append(in.Files, libs...)
-	return &in, nil
+	if !isDir {
+		file, err := files.NewFileFromSource(ytt.NewTrackedFileSource(engine.fs, path, "", dependencies.Add))
+		if err != nil {
+			return nil, err
+		}
+		return []*files.File{file}, nil
+	}
+
+	libs := []*files.File{}
+	err = engine.fs.Walk(path, func(walkedPath string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+		file, err := files.NewFileFromSource(ytt.NewTrackedFileSource(engine.fs, walkedPath, path, dependencies.Add))
+		if err != nil {
+			return err
+		}
+		libs = append(libs, file)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Listing files '%s': %s", path, err)
+	}
+	sort.Slice(libs, func(i, j int) bool {
+		return libs[i].RelativePath() < libs[j].RelativePath()
+	})
+	return libs, nil
 }
 
-func (engine *YttTemplateEngine) apply(workflowName string, templateDir string) (string, error) {
+func (engine *YttTemplateEngine) apply(workflowName string, templateDir string, dependencies workflow.DependencySet) (string, map[string]*workflow.SourcePosition, error) {
 	ui := cmdcore.NewPlainUI(false)
-	in, err := engine.getInput(workflowName, templateDir)
+	in, err := engine.getInput(workflowName, templateDir, dependencies)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 	rootLibrary := workspace.NewRootLibrary(in.Files)
 
@@ -239,12 +311,12 @@ func (engine *YttTemplateEngine) apply(workflowName string, templateDir string)
 
 	values, libraryValues, err := libraryLoader.Values([]*workspace.DataValues{}, &schema.AnySchema{})
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
 	result, err := libraryLoader.Eval(values, libraryValues)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
 	workflowContent := ""
@@ -253,7 +325,61 @@ func (engine *YttTemplateEngine) apply(workflowName string, templateDir string)
 		workflowContent = workflowContent + string(file.Bytes())
 	}
 
-	return workflowContent, nil
+	return workflowContent, engine.getSourcePositions(result.DocSet, in.Files), nil
+}
+
+// getSourcePositions - returns the positions of the nodes in the evaluated documents, keyed by
+// path. ytt keeps track of the template line each node was evaluated from (though not the column).
+func (engine *YttTemplateEngine) getSourcePositions(docSet *yamlmeta.DocumentSet, inputFiles []*files.File) map[string]*workflow.SourcePosition {
+	paths := make(map[string]string)
+	for _, file := range inputFiles {
+		// ytt positions refer to the relative path of the file, but both ytt.FileSource and
+		// files.LocalSource describe themselves with the full path
+		description := file.Description()
+		if strings.HasPrefix(description, "file '") {
+			paths[file.RelativePath()] = strings.TrimSuffix(strings.TrimPrefix(description, "file '"), "'")
+		}
+	}
+
+	positions := make(map[string]*workflow.SourcePosition)
+	var visit func(node interface{}, path string)
+	visit = func(node interface{}, path string) {
+		switch typedNode := node.(type) {
+		case *yamlmeta.Map:
+			for _, item := range typedNode.Items {
+				childPath := yamlutil.JoinPath(path, fmt.Sprintf("%v", item.Key))
+				engine.addSourcePosition(positions, childPath, item.Position, paths)
+				visit(item.Value, childPath)
+			}
+		case *yamlmeta.Array:
+			for i, item := range typedNode.Items {
+				childPath := yamlutil.JoinPath(path, strconv.Itoa(i))
+				engine.addSourcePosition(positions, childPath, item.Position, paths)
+				visit(item.Value, childPath)
+			}
+		}
+	}
+	for _, doc := range docSet.Items {
+		visit(doc.Value, "")
+	}
+	return positions
+}
+
+func (engine *YttTemplateEngine) addSourcePosition(positions map[string]*workflow.SourcePosition, path string, position *filepos.Position, paths map[string]string) {
+	if !position.IsKnown() {
+		return
+	}
+	// AsCompactString returns "<relative path>:<line>"
+	compact := position.AsCompactString()
+	separator := strings.LastIndex(compact, ":")
+	if separator < 0 {
+		return
+	}
+	file, found := paths[compact[:separator]]
+	if !found {
+		return
+	}
+	positions[path] = &workflow.SourcePosition{File: file, Line: position.Line()}
 }
 
 func (engine *YttTemplateEngine) getWorkflowName(workflowsDir string, filename string) string {
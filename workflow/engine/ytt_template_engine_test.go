@@ -22,7 +22,8 @@ func newYttTemplateEngine(config string) (*content.Container, *config.GFlowsCont
 	repoManager := content.NewRepoManager(container.GitAdapter(), container.FileSystem(), container.Logger())
 	installer := env.NewGFlowsLibInstaller(container.FileSystem(), container.ContentReader(), container.ContentWriter(), container.Logger(), repoManager)
 	env := env.NewGFlowsEnv(container.FileSystem(), installer, context, container.Logger())
-	templateEngine := NewYttTemplateEngine(container.FileSystem(), context, container.ContentWriter(), env, container.Logger())
+	buildCache := workflow.NewBuildCache(container.FileSystem(), container.Logger(), context)
+	templateEngine := NewYttTemplateEngine(container.FileSystem(), context, container.ContentWriter(), env, container.Logger(), buildCache)
 	return container, context, templateEngine, roundTripper
 }
 
@@ -44,49 +45,77 @@ func TestGenerateYttWorkflowDefinitions(t *testing.T) {
 	expectedContent := "# File generated by gflows, do not modify\n# Source: .gflows/workflows/test\n"
 	expectedJson, _ := yamlutil.YamlToJson(expectedContent)
 	expectedDefinition := workflow.Definition{
-		Name:        "test",
-		Source:      ".gflows/workflows/test",
-		Destination: ".github/workflows/test.yml",
-		Description: ".gflows/workflows/test",
-		Content:     expectedContent,
-		Status:      workflow.ValidationResult{Valid: true},
-		JSON:        expectedJson,
+		Name:         "test",
+		Source:       ".gflows/workflows/test",
+		Destination:  ".github/workflows/test.yml",
+		Description:  ".gflows/workflows/test",
+		Content:      expectedContent,
+		Header:       "# File generated by gflows, do not modify\n# Source: .gflows/workflows/test\n",
+		Dependencies: []string{".gflows/workflows/test/config.yml"},
+		Status:       workflow.ValidationResult{Valid: true},
+		JSON:         expectedJson,
+		Positions:    make(map[string]*workflow.SourcePosition),
 	}
 	assert.Equal(t, []*workflow.Definition{&expectedDefinition}, definitions)
 }
 
+func TestYttWorkflowDefinitionPositions(t *testing.T) {
+	container, _, templateEngine, _ := newYttTemplateEngine("templates:\n  engine: ytt")
+	fs := container.FileSystem()
+	fs.WriteFile(".gflows/workflows/test/config.yml", []byte(strings.Join([]string{
+		"#@ steps = [{'run': 'echo hello'}]",
+		"jobs:",
+		"  test:",
+		"    runs-on: ubuntu-latest",
+		"    steps: #@ steps",
+	}, "\n")), 0644)
+
+	definitions, _ := templateEngine.GetWorkflowDefinitions()
+
+	positions := definitions[0].Positions
+	assert.Equal(t, &workflow.SourcePosition{File: ".gflows/workflows/test/config.yml", Line: 3}, positions["jobs.test"])
+	assert.Equal(t, &workflow.SourcePosition{File: ".gflows/workflows/test/config.yml", Line: 4}, positions["jobs.test.runs-on"])
+	// nodes created by starlark code have no template position, so point at the generated content
+	assert.Equal(t, ".github/workflows/test.yml", positions["jobs.test.steps.0.run"].File)
+}
+
 func TestGetYttObservableSources(t *testing.T) {
 	config := strings.Join([]string{
 		"templates:",
 		"  engine: ytt",
 		"  defaults:",
 		"    libs:",
-		"    - vendor",
-		"    - foo/bar.yml",
+		"    - ../vendor",
 		"    - https://example.com/config.yml",
 	}, "\n")
 	container, _, templateEngine, _ := newYttTemplateEngine(config)
 	fs := container.FileSystem()
-	fs.WriteFile(".gflows/workflows/my-workflow/config1.yml", []byte(""), 0644)
+	fs.WriteFile(".gflows/workflows/my-workflow/config1.yml", []byte(strings.Join([]string{
+		"#@ load(\"steps.lib.yml\", \"steps\")",
+		"#@ load(\"vendor.lib.yml\", \"vendor\")",
+		"steps: #@ steps + vendor",
+	}, "\n")), 0644)
 	fs.WriteFile(".gflows/workflows/my-workflow/config2.yaml", []byte(""), 0644)
 	fs.WriteFile(".gflows/workflows/my-workflow/config3.txt", []byte(""), 0644)
 	fs.WriteFile(".gflows/workflows/my-workflow/invalid.ext", []byte(""), 0644)
-	fs.WriteFile(".gflows/workflows/lib.yml", []byte(""), 0644)
-	fs.WriteFile(".gflows/libs/lib.yml", []byte(""), 0644)
-	fs.WriteFile("vendor/lib/config.yml", []byte(""), 0644)
-	fs.WriteFile("foo/bar.yml", []byte(""), 0644)
+	fs.WriteFile(".gflows/libs/steps.lib.yml", []byte("#@ steps = []"), 0644)
+	// ytt evaluates every library file it recognises (including starlark files, which previously
+	// weren't observed), but not other files
+	fs.WriteFile(".gflows/libs/helpers.star", []byte("helpers = []"), 0644)
+	fs.WriteFile(".gflows/libs/README.md", []byte("# Libs"), 0644)
+	fs.WriteFile("vendor/vendor.lib.yml", []byte("#@ vendor = []"), 0644)
 
-	sources, err := templateEngine.GetObservableSources()
+	definitions, _ := templateEngine.GetWorkflowDefinitions()
+	sources, err := templateEngine.GetObservableSources(definitions)
 
 	assert.NoError(t, err)
 	assert.Equal(t, []string{
-		"vendor/lib/config.yml",
-		"foo/bar.yml",
-		".gflows/workflows/lib.yml",
+		".gflows/libs/helpers.star",
+		".gflows/libs/steps.lib.yml",
 		".gflows/workflows/my-workflow/config1.yml",
 		".gflows/workflows/my-workflow/config2.yaml",
 		".gflows/workflows/my-workflow/config3.txt",
-		".gflows/libs/lib.yml",
+		"vendor/vendor.lib.yml",
 	}, sources)
 }
 
@@ -0,0 +1,51 @@
+package workflow
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// WorkflowFilter - selects workflows by name, using glob patterns (e.g. "deploy-*"). A nil filter,
+// or one without any patterns, selects all workflows other than those excluded.
+type WorkflowFilter struct {
+	Patterns []string
+	Excludes []string
+}
+
+// NewWorkflowFilter - creates a filter which selects workflows matching any of the patterns (or all
+// workflows if there are none), except those matching any of the excludes
+func NewWorkflowFilter(patterns []string, excludes []string) (*WorkflowFilter, error) {
+	for _, pattern := range append(append([]string{}, patterns...), excludes...) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("Invalid workflow pattern %q: %s", pattern, err)
+		}
+	}
+	return &WorkflowFilter{Patterns: patterns, Excludes: excludes}, nil
+}
+
+// Matches - returns true if the workflow with the given name is selected by the filter
+func (filter *WorkflowFilter) Matches(name string) bool {
+	if filter == nil {
+		return true
+	}
+	if matchesAny(filter.Excludes, name) {
+		return false
+	}
+	return len(filter.Patterns) == 0 || matchesAny(filter.Patterns, name)
+}
+
+// IsSelective - returns true if the filter selects workflows by pattern (as opposed to selecting
+// all workflows, perhaps with some excluded)
+func (filter *WorkflowFilter) IsSelective() bool {
+	return filter != nil && len(filter.Patterns) > 0
+}
+
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		// patterns are validated when the filter is created
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
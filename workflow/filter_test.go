@@ -0,0 +1,40 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowFilter(t *testing.T) {
+	scenarios := []struct {
+		description string
+		patterns    []string
+		excludes    []string
+		name        string
+		expected    bool
+	}{
+		{description: "no patterns", name: "test", expected: true},
+		{description: "matching name", patterns: []string{"test"}, name: "test", expected: true},
+		{description: "other name", patterns: []string{"test"}, name: "other", expected: false},
+		{description: "matching glob", patterns: []string{"other", "deploy-*"}, name: "deploy-prod", expected: true},
+		{description: "excluded", excludes: []string{"deploy-*"}, name: "deploy-prod", expected: false},
+		{description: "matching and excluded", patterns: []string{"deploy-*"}, excludes: []string{"*-prod"}, name: "deploy-prod", expected: false},
+	}
+	for _, scenario := range scenarios {
+		filter, err := NewWorkflowFilter(scenario.patterns, scenario.excludes)
+		assert.NoError(t, err)
+		assert.Equal(t, scenario.expected, filter.Matches(scenario.name), scenario.description)
+	}
+}
+
+func TestNilWorkflowFilter(t *testing.T) {
+	var filter *WorkflowFilter
+	assert.True(t, filter.Matches("test"))
+	assert.False(t, filter.IsSelective())
+}
+
+func TestInvalidWorkflowFilter(t *testing.T) {
+	_, err := NewWorkflowFilter([]string{"[deploy"}, nil)
+	assert.EqualError(t, err, `Invalid workflow pattern "[deploy": syntax error in pattern`)
+}
@@ -1,6 +1,46 @@
 package workflow
 
+import (
+	"path/filepath"
+	"strings"
+)
+
+// GeneratedHeader - the first line of workflows generated by gflows
+const GeneratedHeader = "# File generated by gflows, do not modify"
+
+// WorkflowExtensions - the extensions of workflow files in the GitHub workflows directory
+var WorkflowExtensions = []string{".yml", ".yaml"}
+
+// IsWorkflowFile - returns true if the path has one of the WorkflowExtensions
+func IsWorkflowFile(path string) bool {
+	for _, extension := range WorkflowExtensions {
+		if filepath.Ext(path) == extension {
+			return true
+		}
+	}
+	return false
+}
+
+// WorkflowOwnership - whether a workflow file under .github/workflows is managed by gflows
+type WorkflowOwnership string
+
+const (
+	// Managed - the workflow is generated from a template
+	Managed WorkflowOwnership = "managed"
+	// Orphaned - the workflow was generated by gflows, but its template no longer exists
+	Orphaned WorkflowOwnership = "orphaned"
+	// Unmanaged - the workflow was not generated by gflows
+	Unmanaged WorkflowOwnership = "unmanaged"
+)
+
 type GitHubWorkflow struct {
 	Path       string
 	Definition *Definition
+	Ownership  WorkflowOwnership
+}
+
+// Name - the name of the workflow (i.e. its file name, without the extension), which filters
+// are matched against
+func (gitHubWorkflow *GitHubWorkflow) Name() string {
+	return strings.TrimSuffix(filepath.Base(gitHubWorkflow.Path), filepath.Ext(gitHubWorkflow.Path))
 }
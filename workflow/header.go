@@ -0,0 +1,172 @@
+package workflow
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/jbrunton/gflows/config"
+	"github.com/jbrunton/gflows/io/pkg"
+)
+
+// DefaultHeaderTemplate - the header added to generated workflows unless another is configured
+const DefaultHeaderTemplate = GeneratedHeader + "\n# Source: {{ .Source }}\n"
+
+// ContentOptions - options for formatting the generated content of a workflow
+type ContentOptions struct {
+	// KeyOrder - config.KeyOrderPreserve or config.KeyOrderGitHub
+	KeyOrder string
+
+	// HeaderTemplate - a text/template for the header, which must only contain comments. Defaults
+	// to DefaultHeaderTemplate if empty. See IsGenerated for how custom headers are recognised.
+	HeaderTemplate string
+
+	// IncludeHash - if true, the header ends with a hash of the body so that hand edits can be
+	// detected
+	IncludeHash bool
+
+	// Version - the gflows version, for the header
+	Version string
+}
+
+// HeaderData - the values available to header templates
+type HeaderData struct {
+	Workflow string
+	Source   string
+	Package  string
+	Version  string
+}
+
+// NewContentOptions - returns the content options configured for the workflow
+func NewContentOptions(context *config.GFlowsContext, workflowName string) ContentOptions {
+	headerTemplate := context.Config.GetWorkflowStringProperty(workflowName, func(config *config.GFlowsWorkflowConfig) string {
+		return config.Header.Template
+	})
+	includeHash := context.Config.GetWorkflowBoolProperty(workflowName, false, func(config *config.GFlowsWorkflowConfig) *bool {
+		return config.Header.Hash
+	})
+	return ContentOptions{
+		KeyOrder:       context.Config.GetWorkflowKeyOrder(workflowName),
+		HeaderTemplate: headerTemplate,
+		IncludeHash:    includeHash,
+		Version:        context.Version,
+	}
+}
+
+// renderHeader - renders the header for the workflow body
+func (opts ContentOptions) renderHeader(workflowName string, body string, template *pkg.PathInfo) (string, error) {
+	data := HeaderData{
+		Workflow: workflowName,
+		Source:   template.Description,
+		Package:  template.PackageName,
+		Version:  opts.Version,
+	}
+	headerTemplate := opts.HeaderTemplate
+	if headerTemplate == "" {
+		headerTemplate = DefaultHeaderTemplate
+	}
+	header, err := executeHeaderTemplate(headerTemplate, data)
+	if err != nil {
+		return "", err
+	}
+	if opts.IncludeHash {
+		header = header + fmt.Sprintf("%s%s\n", hashLinePrefix, HashBody(body))
+	}
+	return header, nil
+}
+
+func executeHeaderTemplate(text string, data HeaderData) (string, error) {
+	tmpl, err := template.New("header").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid header template: %s", err)
+	}
+	var out bytes.Buffer
+	err = tmpl.Execute(&out, data)
+	if err != nil {
+		return "", fmt.Errorf("invalid header template: %s", err)
+	}
+
+	header := out.String()
+	if header == "" {
+		return "", nil
+	}
+	if !strings.HasSuffix(header, "\n") {
+		header = header + "\n"
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(header, "\n"), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			return "", errors.New("invalid header template: each line of the header must be a comment")
+		}
+	}
+	return header, nil
+}
+
+// IsGenerated - returns true if the content was generated by gflows: i.e. if its header was
+// rendered from the default template or one of the given header templates, or includes a hash.
+// Custom headers are recognised by the literal text of their template before its first action
+// (e.g. "# Generated from " for "# Generated from {{ .Source }}"), so headers which start with an
+// action can only be recognised if they include a hash.
+func IsGenerated(content string, headerTemplates []string) bool {
+	if strings.HasPrefix(content, GeneratedHeader) {
+		return true
+	}
+	if _, _, hasHash := FindBodyHash(content); hasHash {
+		return true
+	}
+	for _, headerTemplate := range headerTemplates {
+		prefix := headerPrefix(headerTemplate)
+		if prefix != "" && strings.HasPrefix(content, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// headerPrefix - returns the literal text of the header template before its first action, or an
+// empty string if there's nothing more than comment markers to recognise
+func headerPrefix(headerTemplate string) string {
+	prefix := headerTemplate
+	if index := strings.Index(headerTemplate, "{{"); index >= 0 {
+		prefix = headerTemplate[:index]
+		if strings.HasPrefix(headerTemplate[index:], "{{- ") {
+			// the action trims the whitespace before it
+			prefix = strings.TrimRight(prefix, " \t\r\n")
+		}
+	}
+	if strings.Trim(prefix, "# \t\r\n") == "" {
+		return ""
+	}
+	return prefix
+}
+
+const hashLinePrefix = "# gflows-hash: sha256:"
+
+var hashLinePattern = regexp.MustCompile(`^` + regexp.QuoteMeta(hashLinePrefix) + `([0-9a-f]{64})\n?$`)
+
+// HashBody - returns the hex encoded sha256 hash of the workflow body
+func HashBody(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:])
+}
+
+// FindBodyHash - returns the hash recorded in the header of the workflow content, and the body
+// which follows it. Returns false if the header has no hash.
+func FindBodyHash(content string) (string, string, bool) {
+	offset := 0
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if !strings.HasPrefix(line, "#") {
+			// the hash is the last line of the header, so stop at the end of the leading comments
+			break
+		}
+		if matches := hashLinePattern.FindStringSubmatch(line); matches != nil {
+			return matches[1], content[offset+len(line):], true
+		}
+		offset += len(line)
+	}
+	return "", "", false
+}
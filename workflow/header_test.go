@@ -0,0 +1,123 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/jbrunton/gflows/fixtures"
+	"github.com/jbrunton/gflows/io/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewContentOptions(t *testing.T) {
+	_, context, _ := fixtures.NewTestContext(`
+templates:
+  engine: jsonnet
+workflows:
+  defaults:
+    header:
+      template: "# Generated from {{ .Source }}"
+  overrides:
+    my-workflow:
+      header:
+        hash: true
+`)
+	context.Version = "1.2.3"
+
+	assert.Equal(t, ContentOptions{
+		KeyOrder:       "preserve",
+		HeaderTemplate: "# Generated from {{ .Source }}",
+		IncludeHash:    false,
+		Version:        "1.2.3",
+	}, NewContentOptions(context, "other-workflow"))
+	assert.True(t, NewContentOptions(context, "my-workflow").IncludeHash)
+}
+
+func TestRenderHeader(t *testing.T) {
+	template := &pkg.PathInfo{Description: "my-pkg/workflows/test.jsonnet", PackageName: "my-pkg"}
+	scenarios := []struct {
+		description    string
+		opts           ContentOptions
+		expectedHeader string
+		expectedError  string
+	}{
+		{
+			description:    "default header",
+			opts:           ContentOptions{},
+			expectedHeader: "# File generated by gflows, do not modify\n# Source: my-pkg/workflows/test.jsonnet\n",
+		},
+		{
+			description:    "custom header",
+			opts:           ContentOptions{HeaderTemplate: "# {{ .Workflow }} generated by gflows {{ .Version }} from {{ .Package }}", Version: "1.2.3"},
+			expectedHeader: "# test generated by gflows 1.2.3 from my-pkg\n",
+		},
+		{
+			description:    "header with hash",
+			opts:           ContentOptions{HeaderTemplate: "# Generated by gflows\n", IncludeHash: true},
+			expectedHeader: "# Generated by gflows\n# gflows-hash: sha256:" + HashBody("jobs: {}\n") + "\n",
+		},
+		{
+			description:   "invalid template",
+			opts:          ContentOptions{HeaderTemplate: "# {{ .Foo }}"},
+			expectedError: "invalid header template: template: header:1:5: executing \"header\" at <.Foo>: can't evaluate field Foo in type workflow.HeaderData",
+		},
+		{
+			description:   "uncommented header",
+			opts:          ContentOptions{HeaderTemplate: "# Generated by gflows\nDo not edit"},
+			expectedError: "invalid header template: each line of the header must be a comment",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		header, err := scenario.opts.renderHeader("test", "jobs: {}\n", template)
+		if scenario.expectedError == "" {
+			assert.NoError(t, err, "Unexpected error in scenario %q", scenario.description)
+		} else {
+			assert.EqualError(t, err, scenario.expectedError, "Unexpected error in scenario %q", scenario.description)
+		}
+		assert.Equal(t, scenario.expectedHeader, header, "Unexpected header in scenario %q", scenario.description)
+	}
+}
+
+func TestFindBodyHash(t *testing.T) {
+	hash := HashBody("jobs: {}\n")
+	content := "# Generated by gflows\n# gflows-hash: sha256:" + hash + "\njobs: {}\n"
+
+	actualHash, body, ok := FindBodyHash(content)
+
+	assert.True(t, ok)
+	assert.Equal(t, hash, actualHash)
+	assert.Equal(t, "jobs: {}\n", body)
+
+	_, _, ok = FindBodyHash("# Generated by gflows\njobs: {}\n# gflows-hash: sha256:" + hash + "\n")
+	assert.False(t, ok, "Expected hash to be ignored outside the header")
+}
+
+func TestIsGenerated(t *testing.T) {
+	headerTemplates := []string{"# Generated from {{ .Source }}", "{{ .Workflow }} workflow", "# {{ .Workflow }}"}
+	scenarios := []struct {
+		description string
+		content     string
+		expected    bool
+	}{
+		{"default header", "# File generated by gflows, do not modify\n# Source: test.jsonnet\njobs: {}\n", true},
+		{"custom header", "# Generated from test.jsonnet\njobs: {}\n", true},
+		{"header with hash", "# test\n# gflows-hash: sha256:" + HashBody("jobs: {}\n") + "\njobs: {}\n", true},
+		{"header starting with an action", "test workflow\njobs: {}\n", false},
+		{"header with only a comment marker", "# test\njobs: {}\n", false},
+		{"unmanaged workflow", "# My workflow\njobs: {}\n", false},
+	}
+	for _, scenario := range scenarios {
+		assert.Equal(t, scenario.expected, IsGenerated(scenario.content, headerTemplates), scenario.description)
+	}
+}
+
+func TestSetContentWithHash(t *testing.T) {
+	definition := &Definition{Name: "test", Destination: ".github/workflows/test.yml"}
+
+	definition.SetContent("jobs: {}\n", &pkg.PathInfo{Description: "test.jsonnet"}, ContentOptions{IncludeHash: true})
+
+	assert.Equal(t, "jobs: {}\n", definition.Body())
+	hash, body, ok := FindBodyHash(definition.Content)
+	assert.True(t, ok)
+	assert.Equal(t, HashBody(body), hash)
+}
@@ -0,0 +1,55 @@
+package workflow
+
+import (
+	"sync"
+
+	"github.com/jbrunton/gflows/io/pkg"
+)
+
+// RunParallel - invokes fn for each index in [0, count) using at most jobs goroutines, and waits
+// for them all to complete. Callers should store results by index so that output order doesn't
+// depend on scheduling.
+func RunParallel(count int, jobs int, fn func(i int)) {
+	if jobs < 1 {
+		jobs = 1
+	}
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < jobs && worker < count; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < count; i++ {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+}
+
+// GetDefinitionsInParallel - evaluates each template with getDefinition using at most jobs
+// goroutines. Definitions are returned in the order of the templates, skipping nil definitions
+// (i.e. templates excluded by a filter). If any template fails, the error for the first such
+// template is returned.
+func GetDefinitionsInParallel(templates []*pkg.PathInfo, jobs int, getDefinition func(template *pkg.PathInfo) (*Definition, error)) ([]*Definition, error) {
+	results := make([]*Definition, len(templates))
+	errors := make([]error, len(templates))
+	RunParallel(len(templates), jobs, func(i int) {
+		results[i], errors[i] = getDefinition(templates[i])
+	})
+
+	definitions := []*Definition{}
+	for i, definition := range results {
+		if errors[i] != nil {
+			return []*Definition{}, errors[i]
+		}
+		if definition != nil {
+			definitions = append(definitions, definition)
+		}
+	}
+	return definitions, nil
+}
@@ -0,0 +1,71 @@
+package workflow
+
+import (
+	"errors"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jbrunton/gflows/io/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunParallel(t *testing.T) {
+	results := make([]int, 20)
+	var running, maxRunning int32
+
+	RunParallel(len(results), 3, func(i int) {
+		current := atomic.AddInt32(&running, 1)
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if current <= max || atomic.CompareAndSwapInt32(&maxRunning, max, current) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		results[i] = i * i
+		atomic.AddInt32(&running, -1)
+	})
+
+	for i, result := range results {
+		assert.Equal(t, i*i, result)
+	}
+	assert.LessOrEqual(t, maxRunning, int32(3))
+}
+
+func TestGetDefinitionsInParallel(t *testing.T) {
+	templates := []*pkg.PathInfo{}
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		templates = append(templates, &pkg.PathInfo{LocalPath: name})
+	}
+
+	definitions, err := GetDefinitionsInParallel(templates, 4, func(template *pkg.PathInfo) (*Definition, error) {
+		// finish in a random order
+		time.Sleep(time.Duration(rand.Intn(5)) * time.Millisecond)
+		if template.LocalPath == "c" {
+			return nil, nil
+		}
+		return &Definition{Name: template.LocalPath}, nil
+	})
+
+	assert.NoError(t, err)
+	names := []string{}
+	for _, definition := range definitions {
+		names = append(names, definition.Name)
+	}
+	assert.Equal(t, []string{"a", "b", "d", "e"}, names)
+}
+
+func TestGetDefinitionsInParallelErrors(t *testing.T) {
+	templates := []*pkg.PathInfo{{LocalPath: "a"}, {LocalPath: "b"}, {LocalPath: "c"}}
+
+	_, err := GetDefinitionsInParallel(templates, 3, func(template *pkg.PathInfo) (*Definition, error) {
+		if template.LocalPath == "a" {
+			return &Definition{Name: "a"}, nil
+		}
+		return nil, errors.New("error in " + template.LocalPath)
+	})
+
+	assert.EqualError(t, err, "error in b")
+}
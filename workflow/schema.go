@@ -0,0 +1,280 @@
+package workflow
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jbrunton/gflows/config"
+	"github.com/jbrunton/gflows/io"
+	"github.com/jbrunton/gflows/io/content"
+	"github.com/jbrunton/gflows/io/pkg"
+	_ "github.com/jbrunton/gflows/static/statik"
+	statikFs "github.com/rakyll/statik/fs"
+	"github.com/spf13/afero"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// GitHubWorkflowSchemaURI - the published location of the GitHub workflow schema
+const GitHubWorkflowSchemaURI = "https://json.schemastore.org/github-workflow"
+
+// SchemaCacheTTL - how long downloaded schemas are reused before they are downloaded again
+const SchemaCacheTTL = 24 * time.Hour
+
+// SchemaCache - loads and compiles schemas, keeping compiled schemas in memory and downloaded
+// schemas on disk so that each schema is only fetched and compiled once. Safe for concurrent use.
+type SchemaCache struct {
+	fs       *afero.Afero
+	reader   *content.Reader
+	logger   *io.Logger
+	context  *config.GFlowsContext
+	cacheDir string
+	entries  map[string]*schemaEntry
+	mutex    sync.Mutex
+}
+
+// schemaEntry - the sources and compiled schema for a URI. Each entry has its own lock, so that
+// downloading one schema doesn't block requests for any others.
+type schemaEntry struct {
+	// sources - the source of the schema, and of any remote schemas it references, by URI. Read
+	// once per run, so that each schema is only downloaded once even with --refresh-schemas.
+	sources map[string]string
+	schema  *gojsonschema.Schema
+	mutex   sync.Mutex
+}
+
+// NewSchemaCache - creates a new schema cache, storing downloaded schemas in the user cache dir
+func NewSchemaCache(fs *afero.Afero, reader *content.Reader, logger *io.Logger, context *config.GFlowsContext) *SchemaCache {
+	return &SchemaCache{
+		fs:       fs,
+		reader:   reader,
+		logger:   logger,
+		context:  context,
+		cacheDir: io.CacheDir("schemas"),
+		entries:  make(map[string]*schemaEntry),
+	}
+}
+
+// GetSchema - returns the compiled schema for the given URI. Remote URIs are downloaded (or read
+// from the disk cache), any other URI is treated as a path to a local file. If the URI is empty
+// then the cached copy of the GitHub workflow schema is used if there is one, and the bundled
+// copy otherwise.
+func (cache *SchemaCache) GetSchema(uri string) (*gojsonschema.Schema, error) {
+	entry := cache.getEntry(uri)
+	entry.mutex.Lock()
+	defer entry.mutex.Unlock()
+
+	if entry.schema != nil {
+		// already compiled
+		return entry.schema, nil
+	}
+
+	sources, err := cache.loadSources(uri, entry)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := compileSchema(uri, sources)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid schema at %s: %s", uri, err)
+	}
+
+	entry.schema = schema
+	return schema, nil
+}
+
+// getSchemaSources - returns the source of the schema for the given URI, and of any remote
+// schemas it references, without compiling it
+func (cache *SchemaCache) getSchemaSources(uri string) (map[string]string, error) {
+	entry := cache.getEntry(uri)
+	entry.mutex.Lock()
+	defer entry.mutex.Unlock()
+	return cache.loadSources(uri, entry)
+}
+
+// loadSources - reads the sources for the entry, if they haven't already been read. The caller
+// must hold the entry's lock.
+func (cache *SchemaCache) loadSources(uri string, entry *schemaEntry) (map[string]string, error) {
+	if entry.sources != nil {
+		return entry.sources, nil
+	}
+	sources := make(map[string]string)
+	pending := []string{uri}
+	for len(pending) > 0 {
+		next := pending[0]
+		pending = pending[1:]
+		source, err := cache.readSchema(next)
+		if err != nil {
+			return nil, err
+		}
+		sources[next] = source
+		refs, err := findRemoteRefs(next, source)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid schema at %s: %s", next, err)
+		}
+		for _, ref := range refs {
+			if _, ok := sources[ref]; !ok && !contains(pending, ref) {
+				pending = append(pending, ref)
+			}
+		}
+	}
+	entry.sources = sources
+	return sources, nil
+}
+
+func (cache *SchemaCache) getEntry(uri string) *schemaEntry {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	entry := cache.entries[uri]
+	if entry == nil {
+		entry = &schemaEntry{}
+		cache.entries[uri] = entry
+	}
+	return entry
+}
+
+// compileSchema - compiles the schema. Remote schemas are added under their URI, so that relative
+// references are resolved against it, and so that the referenced schemas are read from sources
+// rather than downloaded again.
+func compileSchema(uri string, sources map[string]string) (*gojsonschema.Schema, error) {
+	loader := gojsonschema.NewSchemaLoader()
+	for sourceURI, source := range sources {
+		if !pkg.IsRemotePath(sourceURI) {
+			continue
+		}
+		err := loader.AddSchema(sourceURI, gojsonschema.NewStringLoader(source))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !pkg.IsRemotePath(uri) {
+		return loader.Compile(gojsonschema.NewStringLoader(sources[uri]))
+	}
+	return loader.Compile(gojsonschema.NewReferenceLoader(uri))
+}
+
+// findRemoteRefs - returns the remote schemas referenced by the schema at the given URI (with any
+// fragments removed)
+func findRemoteRefs(uri string, source string) ([]string, error) {
+	var document interface{}
+	err := json.Unmarshal([]byte(source), &document)
+	if err != nil {
+		return nil, err
+	}
+	base, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	var refs []string
+	var visit func(node interface{})
+	visit = func(node interface{}) {
+		switch node := node.(type) {
+		case map[string]interface{}:
+			for key, value := range node {
+				if ref, ok := value.(string); ok && key == "$ref" {
+					refURL, err := url.Parse(ref)
+					if err != nil {
+						continue
+					}
+					refURL = base.ResolveReference(refURL)
+					refURL.Fragment = ""
+					if resolved := refURL.String(); pkg.IsRemotePath(resolved) && resolved != uri {
+						refs = append(refs, resolved)
+					}
+					continue
+				}
+				visit(value)
+			}
+		case []interface{}:
+			for _, value := range node {
+				visit(value)
+			}
+		}
+	}
+	visit(document)
+	sort.Strings(refs)
+	return refs, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (cache *SchemaCache) readSchema(uri string) (string, error) {
+	if uri == "" {
+		return cache.readDefaultSchema()
+	}
+	if pkg.IsRemotePath(uri) {
+		return cache.readRemoteSchema(uri)
+	}
+	source, err := cache.fs.ReadFile(cache.context.ResolvePath(uri))
+	return string(source), err
+}
+
+func (cache *SchemaCache) readDefaultSchema() (string, error) {
+	cachePath := cache.context.SchemaCachePath()
+	exists, err := cache.fs.Exists(cachePath)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return ReadBundledSchema()
+	}
+	source, err := cache.fs.ReadFile(cachePath)
+	return string(source), err
+}
+
+func (cache *SchemaCache) readRemoteSchema(uri string) (string, error) {
+	cachePath := filepath.Join(cache.cacheDir, fmt.Sprintf("%x.json", sha256.Sum256([]byte(uri))))
+	info, err := cache.fs.Stat(cachePath)
+	cached := err == nil
+	fresh := cached && !cache.context.RefreshSchemas && time.Since(info.ModTime()) < SchemaCacheTTL
+	if fresh || (cached && cache.context.Offline) {
+		cache.logger.Debugf("Using cached schema for %s (%s)\n", uri, cachePath)
+		source, err := cache.fs.ReadFile(cachePath)
+		return string(source), err
+	}
+	if cache.context.Offline {
+		return "", fmt.Errorf("Schema %s isn't cached, and can't be downloaded with --offline", uri)
+	}
+
+	cache.logger.Debugf("Downloading schema %s\n", uri)
+	source, err := cache.reader.ReadContent(uri)
+	if err != nil {
+		if cached {
+			// better to validate against a stale schema than to fail outright
+			cache.logger.Warnfln("unable to download %s, using cached copy (%s)", uri, err)
+			source, err := cache.fs.ReadFile(cachePath)
+			return string(source), err
+		}
+		return "", err
+	}
+
+	err = content.NewWriter(cache.fs, cache.logger).SafelyWriteFile(cachePath, source)
+	return source, err
+}
+
+// ReadBundledSchema - returns the copy of the GitHub workflow schema bundled with gflows
+func ReadBundledSchema() (string, error) {
+	sourceFs, err := statikFs.New()
+	if err != nil {
+		return "", err
+	}
+	schemaFile, err := sourceFs.Open("/github-workflow-schema.json")
+	if err != nil {
+		return "", err
+	}
+	defer schemaFile.Close()
+	source, err := ioutil.ReadAll(schemaFile)
+	return string(source), err
+}
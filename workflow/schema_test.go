@@ -0,0 +1,213 @@
+package workflow
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jbrunton/gflows/config"
+	"github.com/jbrunton/gflows/fixtures"
+	"github.com/jbrunton/gflows/io"
+	"github.com/jbrunton/gflows/io/content"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+func newTestSchemaCache(fs *afero.Afero, context *config.GFlowsContext, roundTripper http.RoundTripper) *SchemaCache {
+	reader := content.NewReader(fs, &http.Client{Transport: roundTripper})
+	logger, _ := io.NewTestLogger()
+	return NewSchemaCache(fs, reader, logger, context)
+}
+
+func TestGetBundledSchema(t *testing.T) {
+	container, context, _ := fixtures.NewTestContext("")
+	cache := newTestSchemaCache(container.FileSystem(), context, fixtures.NewMockRoundTripper())
+
+	schema, err := cache.GetSchema("")
+
+	assert.NoError(t, err)
+	definition := newTestWorkflowDefinition("test", fixtures.InvalidJsonnetWorkflow)
+	result, err := schema.Validate(gojsonschema.NewGoLoader(definition.JSON))
+	assert.NoError(t, err)
+	assert.False(t, result.Valid())
+}
+
+func TestGetSchemaCompilesOnce(t *testing.T) {
+	container, context, _ := fixtures.NewTestContext("")
+	cache := newTestSchemaCache(container.FileSystem(), context, fixtures.NewMockRoundTripper())
+
+	schemaOne, err := cache.GetSchema("")
+	assert.NoError(t, err)
+	schemaTwo, err := cache.GetSchema("")
+	assert.NoError(t, err)
+
+	assert.True(t, schemaOne == schemaTwo, "expected same schema")
+}
+
+func TestGetRemoteSchemaUsesDiskCache(t *testing.T) {
+	container, context, _ := fixtures.NewTestContext("")
+	fs := container.FileSystem()
+	roundTripper := fixtures.NewMockRoundTripper()
+	roundTripper.StubBody("https://example.com/schema.json", `{"required": ["jobs"]}`)
+
+	_, err := newTestSchemaCache(fs, context, roundTripper).GetSchema("https://example.com/schema.json")
+	assert.NoError(t, err)
+	_, err = newTestSchemaCache(fs, context, roundTripper).GetSchema("https://example.com/schema.json")
+	assert.NoError(t, err)
+
+	roundTripper.AssertNumberOfCalls(t, "RoundTrip", 1)
+}
+
+func TestGetRemoteSchemaRefresh(t *testing.T) {
+	container, context, _ := fixtures.NewTestContext("")
+	fs := container.FileSystem()
+	roundTripper := fixtures.NewMockRoundTripper()
+	roundTripper.StubBody("https://example.com/schema.json", `{"required": ["jobs"]}`)
+	refreshedRoundTripper := fixtures.NewMockRoundTripper()
+	refreshedRoundTripper.StubBody("https://example.com/schema.json", `{"required": ["name"]}`)
+
+	_, err := newTestSchemaCache(fs, context, roundTripper).GetSchema("https://example.com/schema.json")
+	assert.NoError(t, err)
+	context.RefreshSchemas = true
+	schema, err := newTestSchemaCache(fs, context, refreshedRoundTripper).GetSchema("https://example.com/schema.json")
+	assert.NoError(t, err)
+
+	roundTripper.AssertNumberOfCalls(t, "RoundTrip", 1)
+	refreshedRoundTripper.AssertNumberOfCalls(t, "RoundTrip", 1)
+	result, err := schema.Validate(gojsonschema.NewGoLoader(map[string]interface{}{"jobs": "foo"}))
+	assert.NoError(t, err)
+	assert.False(t, result.Valid(), "expected refreshed schema to require name")
+}
+
+func TestGetRemoteSchemaUsesStaleCopy(t *testing.T) {
+	container, context, _ := fixtures.NewTestContext("")
+	fs := container.FileSystem()
+	roundTripper := fixtures.NewMockRoundTripper()
+	roundTripper.StubBody("https://example.com/schema.json", `{"required": ["jobs"]}`)
+	failingRoundTripper := fixtures.NewMockRoundTripper()
+	failingRoundTripper.StubStatusCode("https://example.com/schema.json", 500)
+
+	_, err := newTestSchemaCache(fs, context, roundTripper).GetSchema("https://example.com/schema.json")
+	assert.NoError(t, err)
+	context.RefreshSchemas = true
+	out, errOut := new(bytes.Buffer), new(bytes.Buffer)
+	reader := content.NewReader(fs, &http.Client{Transport: failingRoundTripper})
+	_, err = NewSchemaCache(fs, reader, io.NewLogger(out, errOut, false, false), context).GetSchema("https://example.com/schema.json")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", out.String())
+	assert.Regexp(t, "^Warning: unable to download https://example.com/schema.json, using cached copy", errOut.String())
+}
+
+func TestGetRemoteSchemaOffline(t *testing.T) {
+	container, context, _ := fixtures.NewTestContext("")
+	fs := container.FileSystem()
+	roundTripper := fixtures.NewMockRoundTripper()
+	roundTripper.StubBody("https://example.com/schema.json", `{"required": ["jobs"]}`)
+	context.Offline = true
+
+	_, err := newTestSchemaCache(fs, context, roundTripper).GetSchema("https://example.com/schema.json")
+	assert.EqualError(t, err, "Schema https://example.com/schema.json isn't cached, and can't be downloaded with --offline")
+
+	context.Offline = false
+	_, err = newTestSchemaCache(fs, context, roundTripper).GetSchema("https://example.com/schema.json")
+	assert.NoError(t, err)
+	context.Offline = true
+	context.RefreshSchemas = true
+	_, err = newTestSchemaCache(fs, context, roundTripper).GetSchema("https://example.com/schema.json")
+	assert.NoError(t, err)
+
+	roundTripper.AssertNumberOfCalls(t, "RoundTrip", 1)
+}
+
+func TestGetRemoteSchemaResolvesRelativeRefs(t *testing.T) {
+	container, context, _ := fixtures.NewTestContext("")
+	roundTripper := fixtures.NewMockRoundTripper()
+	roundTripper.StubBody("https://example.com/schemas/schema.json", `{"$ref": "defs.json#/definitions/workflow"}`)
+	roundTripper.StubBody("https://example.com/schemas/defs.json", `{"definitions": {"workflow": {"required": ["jobs"]}}}`)
+	cache := newTestSchemaCache(container.FileSystem(), context, roundTripper)
+
+	schema, err := cache.GetSchema("https://example.com/schemas/schema.json")
+
+	assert.NoError(t, err)
+	result, err := schema.Validate(gojsonschema.NewGoLoader(map[string]interface{}{"name": "foo"}))
+	assert.NoError(t, err)
+	assert.False(t, result.Valid(), "expected referenced schema to require jobs")
+	sources, err := cache.getSchemaSources("https://example.com/schemas/schema.json")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"https://example.com/schemas/schema.json": `{"$ref": "defs.json#/definitions/workflow"}`,
+		"https://example.com/schemas/defs.json":   `{"definitions": {"workflow": {"required": ["jobs"]}}}`,
+	}, sources)
+	roundTripper.AssertNumberOfCalls(t, "RoundTrip", 2)
+}
+
+func TestGetRemoteSchemaRefreshesOncePerRun(t *testing.T) {
+	container, context, _ := fixtures.NewTestContext("")
+	roundTripper := fixtures.NewMockRoundTripper()
+	roundTripper.StubBody("https://example.com/schema.json", `{"required": ["jobs"]}`)
+	context.RefreshSchemas = true
+	cache := newTestSchemaCache(container.FileSystem(), context, roundTripper)
+
+	for i := 0; i < 3; i++ {
+		_, err := cache.getSchemaSources("https://example.com/schema.json")
+		assert.NoError(t, err)
+		_, err = cache.GetSchema("https://example.com/schema.json")
+		assert.NoError(t, err)
+	}
+
+	roundTripper.AssertNumberOfCalls(t, "RoundTrip", 1)
+}
+
+// blockingRoundTripper - blocks requests for the given URL until released
+type blockingRoundTripper struct {
+	url      string
+	started  chan bool
+	released chan bool
+	*fixtures.MockRoundTripper
+}
+
+func (roundTripper *blockingRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	if request.URL.String() == roundTripper.url {
+		roundTripper.started <- true
+		<-roundTripper.released
+	}
+	return roundTripper.MockRoundTripper.RoundTrip(request)
+}
+
+func TestGetSchemaDoesntBlockOtherSchemas(t *testing.T) {
+	container, context, _ := fixtures.NewTestContext("")
+	roundTripper := &blockingRoundTripper{
+		url:              "https://example.com/slow.json",
+		started:          make(chan bool),
+		released:         make(chan bool),
+		MockRoundTripper: fixtures.NewMockRoundTripper(),
+	}
+	roundTripper.StubBody("https://example.com/slow.json", `{"required": ["jobs"]}`)
+	roundTripper.StubBody("https://example.com/fast.json", `{"required": ["jobs"]}`)
+	cache := newTestSchemaCache(container.FileSystem(), context, roundTripper)
+
+	slowDone := make(chan error)
+	go func() {
+		_, err := cache.GetSchema("https://example.com/slow.json")
+		slowDone <- err
+	}()
+	<-roundTripper.started
+
+	fastDone := make(chan error)
+	go func() {
+		_, err := cache.GetSchema("https://example.com/fast.json")
+		fastDone <- err
+	}()
+	select {
+	case err := <-fastDone:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Error("expected fast.json not to wait for slow.json")
+	}
+
+	close(roundTripper.released)
+	assert.NoError(t, <-slowDone)
+}
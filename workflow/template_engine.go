@@ -5,13 +5,19 @@ import (
 )
 
 type TemplateEngine interface {
-	// GetObservableSources - returns a list of all the local files used to generate workflows. Used
-	// to get the list of files to watch for changes.
-	GetObservableSources() ([]string, error)
+	// GetObservableSources - returns a list of the local files used to generate workflows: the
+	// templates, and the files read when generating the given definitions. Used to get the list of
+	// files to watch for changes. Templates aren't evaluated, so that the watcher can reuse the
+	// definitions it has already generated.
+	GetObservableSources(definitions []*Definition) ([]string, error)
 
 	// GetWorkflowDefinitions - returns definitions generated from workflow templates.
 	GetWorkflowDefinitions() ([]*Definition, error)
 
+	// GetWorkflowDefinitionsMatching - returns definitions generated from the workflow templates
+	// selected by the filter. Other templates aren't evaluated.
+	GetWorkflowDefinitionsMatching(filter *WorkflowFilter) ([]*Definition, error)
+
 	// ImportWorkflow - imports a workflow, returns the path to the new template.
 	ImportWorkflow(workflow *GitHubWorkflow) (string, error)
 
@@ -10,9 +10,10 @@ import (
 
 // Validator - validates a workflow definition
 type Validator struct {
-	fs            *afero.Afero
-	defaultSchema *gojsonschema.Schema
-	config        *config.GFlowsConfig
+	fs          *afero.Afero
+	schemaCache *SchemaCache
+	buildCache  *BuildCache
+	config      *config.GFlowsConfig
 }
 
 // ValidationResult - validate result
@@ -20,20 +21,19 @@ type ValidationResult struct {
 	Valid         bool
 	Errors        []string
 	ActualContent string
+
+	// HandEdited - true if the content check failed because the workflow was edited after it was
+	// generated (which can only be detected if its header includes a hash)
+	HandEdited bool
 }
 
 // NewValidator - creates a new validator for the given filesystem
-func NewValidator(fs *afero.Afero, context *config.GFlowsContext) *Validator {
-	config := context.Config
-	schemaLoader := gojsonschema.NewReferenceLoader(config.Workflows.Defaults.Checks.Schema.URI)
-	defaultSchema, err := gojsonschema.NewSchema(schemaLoader)
-	if err != nil {
-		panic(err)
-	}
+func NewValidator(fs *afero.Afero, schemaCache *SchemaCache, buildCache *BuildCache, context *config.GFlowsContext) *Validator {
 	return &Validator{
-		fs:            fs,
-		defaultSchema: defaultSchema,
-		config:        config,
+		fs:          fs,
+		schemaCache: schemaCache,
+		buildCache:  buildCache,
+		config:      context.Config,
 	}
 }
 
@@ -47,8 +47,25 @@ func (validator *Validator) ValidateSchema(definition *Definition) ValidationRes
 		}
 	}
 
+	// compiling schemas is relatively slow, so check the cache using the schema sources first
+	uri := validator.getWorkflowSchemaURI(definition.Name)
+	sources, err := validator.schemaCache.getSchemaSources(uri)
+	if err != nil {
+		panic(err)
+	}
+	cacheKey, err := validator.buildCache.ValidationKey(definition, sources)
+	if err != nil {
+		panic(err)
+	}
+	if cached := validator.buildCache.GetValidation(cacheKey); cached != nil {
+		return *cached
+	}
+
 	loader := gojsonschema.NewGoLoader(definition.JSON)
-	schema := validator.getWorkflowSchema(definition.Name)
+	schema, err := validator.schemaCache.GetSchema(uri)
+	if err != nil {
+		panic(err)
+	}
 	result, err := schema.Validate(loader)
 	if err != nil {
 		panic(err)
@@ -56,13 +73,20 @@ func (validator *Validator) ValidateSchema(definition *Definition) ValidationRes
 
 	errors := []string{}
 	for _, error := range result.Errors() {
-		errors = append(errors, error.String())
+		message := error.String()
+		property, _ := error.Details()["property"].(string)
+		if position := definition.FindPosition(error.Field(), property); position != nil {
+			message = fmt.Sprintf("%s: %s", position, message)
+		}
+		errors = append(errors, message)
 	}
 
-	return ValidationResult{
+	validationResult := ValidationResult{
 		Valid:  result.Valid(),
 		Errors: errors,
 	}
+	validator.buildCache.PutValidation(cacheKey, validationResult)
+	return validationResult
 }
 
 // ValidateContent - validates the content at the destination in the definition is up to date
@@ -96,6 +120,15 @@ func (validator *Validator) ValidateContent(definition *Definition) ValidationRe
 
 	actualContent := string(data)
 	if actualContent != definition.Content {
+		if hash, body, ok := FindBodyHash(actualContent); ok && hash != HashBody(body) {
+			reason := fmt.Sprintf("Workflow %q (%s) has been edited since it was generated", definition.Name, definition.Destination)
+			return ValidationResult{
+				Valid:         false,
+				Errors:        []string{reason},
+				ActualContent: actualContent,
+				HandEdited:    true,
+			}
+		}
 		reason := fmt.Sprintf("Content is out of date for %q (%s)", definition.Name, definition.Destination)
 		return ValidationResult{
 			Valid:         false,
@@ -110,17 +143,10 @@ func (validator *Validator) ValidateContent(definition *Definition) ValidationRe
 	}
 }
 
-func (validator *Validator) getWorkflowSchema(workflowName string) *gojsonschema.Schema {
-	workflowConfig := validator.config.Workflows.Overrides[workflowName]
-	if workflowConfig == nil || workflowConfig.Checks.Schema.URI == "" {
-		return validator.defaultSchema
-	}
-	schemaLoader := gojsonschema.NewReferenceLoader(workflowConfig.Checks.Schema.URI)
-	schema, err := gojsonschema.NewSchema(schemaLoader)
-	if err != nil {
-		panic(err)
-	}
-	return schema
+func (validator *Validator) getWorkflowSchemaURI(workflowName string) string {
+	return validator.config.GetWorkflowStringProperty(workflowName, func(config *config.GFlowsWorkflowConfig) string {
+		return config.Checks.Schema.URI
+	})
 }
 
 func (validator *Validator) getContentCheckEnabled(definition *Definition) bool {
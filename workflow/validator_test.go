@@ -1,19 +1,31 @@
 package workflow
 
 import (
+	"net/http"
 	"strings"
 	"testing"
 
+	"github.com/jbrunton/gflows/config"
 	"github.com/jbrunton/gflows/fixtures"
+	"github.com/jbrunton/gflows/io"
+	"github.com/jbrunton/gflows/io/content"
+	"github.com/jbrunton/gflows/io/pkg"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 )
 
+func newTestValidator(fs *afero.Afero, context *config.GFlowsContext) *Validator {
+	reader := content.NewReader(fs, &http.Client{Transport: fixtures.NewMockRoundTripper()})
+	logger, _ := io.NewTestLogger()
+	schemaCache := NewSchemaCache(fs, reader, logger, context)
+	return NewValidator(fs, schemaCache, NewBuildCache(fs, logger, context), context)
+}
+
 func setupValidator(workflowContent string, config string) (*afero.Afero, *Validator, *Definition) {
 	container, context, _ := fixtures.NewTestContext(config)
 	fs := container.FileSystem()
 	Definition := newTestWorkflowDefinition("test", workflowContent)
-	validator := NewValidator(fs, context)
+	validator := newTestValidator(fs, context)
 	return fs, validator, Definition
 }
 
@@ -47,6 +59,30 @@ func TestValidateContentOutOfDate(t *testing.T) {
 	assert.Equal(t, []string{"Content is out of date for \"test\" (.github/workflows/test.yml)"}, result.Errors)
 }
 
+func TestValidateContentHandEdited(t *testing.T) {
+	_, validator, definition := setupValidator("", "")
+	opts := ContentOptions{IncludeHash: true}
+	definition.SetContent("jobs: {}\n", &pkg.PathInfo{Description: definition.Source}, opts)
+	generatedContent := definition.Content
+
+	// the template changes, so the workflow is out of date
+	definition.SetContent("jobs: { test: {} }\n", &pkg.PathInfo{Description: definition.Source}, opts)
+	validator.fs.WriteFile(definition.Destination, []byte(generatedContent), 0644)
+	result := validator.ValidateContent(definition)
+
+	assert.False(t, result.Valid)
+	assert.False(t, result.HandEdited)
+	assert.Equal(t, []string{"Content is out of date for \"test\" (.github/workflows/test.yml)"}, result.Errors)
+
+	// someone edits the generated workflow
+	validator.fs.WriteFile(definition.Destination, []byte(generatedContent+"env: {}\n"), 0644)
+	result = validator.ValidateContent(definition)
+
+	assert.False(t, result.Valid)
+	assert.True(t, result.HandEdited)
+	assert.Equal(t, []string{"Workflow \"test\" (.github/workflows/test.yml) has been edited since it was generated"}, result.Errors)
+}
+
 func TestValidateSchema(t *testing.T) {
 	_, validator, definition := setupValidator(fixtures.ExampleWorkflow("test.jsonnet"), "")
 
@@ -65,6 +101,24 @@ func TestValidateSchemaMissingField(t *testing.T) {
 	assert.Equal(t, []string{"(root): jobs is required"}, result.Errors)
 }
 
+func TestValidateSchemaErrorPositions(t *testing.T) {
+	_, validator, definition := setupValidator("", "")
+	definition.SetContent(strings.Join([]string{
+		"\"on\": push",
+		"jobs:",
+		"  test:",
+		"    runs-on: ubuntu-latest",
+		"    steps:",
+		"      - run: echo hello",
+		"        foo: bar",
+	}, "\n"), &pkg.PathInfo{Description: definition.Source}, ContentOptions{})
+
+	result := validator.ValidateSchema(definition)
+
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.Errors, ".github/workflows/test.yml:9:9: jobs.test.steps.0: Additional property foo is not allowed")
+}
+
 func TestValidateContentEnabledFlags(t *testing.T) {
 	scenarios := []struct {
 		config         string
@@ -179,3 +233,71 @@ func TestValidateSchemaEnabledFlags(t *testing.T) {
 		assert.Equal(t, scenario.expectedResult, result)
 	}
 }
+
+func TestValidateSchemaWithCachedSchema(t *testing.T) {
+	container, context, _ := fixtures.NewTestContext("")
+	fs := container.FileSystem()
+	fs.WriteFile(".gflows/schemas/github-workflow.json", []byte(`{"required": ["name"]}`), 0644)
+	definition := newTestWorkflowDefinition("test", fixtures.ExampleWorkflow("test.jsonnet"))
+	validator := newTestValidator(fs, context)
+
+	result := validator.ValidateSchema(definition)
+
+	assert.False(t, result.Valid)
+	assert.Equal(t, []string{"(root): name is required"}, result.Errors)
+}
+
+func TestValidateSchemaWithLocalSchemaOverride(t *testing.T) {
+	config := strings.Join([]string{
+		"templates:",
+		"  engine: ytt",
+		"workflows:",
+		"  overrides:",
+		"    test:",
+		"      checks:",
+		"        schema:",
+		"          uri: schemas/custom.json",
+	}, "\n")
+	container, context, _ := fixtures.NewTestContext(config)
+	fs := container.FileSystem()
+	fs.WriteFile(".gflows/schemas/custom.json", []byte(`{"required": ["name"]}`), 0644)
+	definition := newTestWorkflowDefinition("test", fixtures.ExampleWorkflow("test.jsonnet"))
+	validator := newTestValidator(fs, context)
+
+	result := validator.ValidateSchema(definition)
+
+	assert.False(t, result.Valid)
+	assert.Equal(t, []string{"(root): name is required"}, result.Errors)
+}
+
+func TestValidateSchemaCacheIncludesRefs(t *testing.T) {
+	config := strings.Join([]string{
+		"templates:",
+		"  engine: jsonnet",
+		"workflows:",
+		"  defaults:",
+		"    checks:",
+		"      schema:",
+		"        uri: https://example.com/schemas/schema.json",
+	}, "\n")
+	container, context, _ := fixtures.NewTestContext(config)
+	fs := container.FileSystem()
+	definition := newTestWorkflowDefinition("test", fixtures.ExampleWorkflow("test.jsonnet"))
+	newValidator := func(defs string) *Validator {
+		roundTripper := fixtures.NewMockRoundTripper()
+		roundTripper.StubBody("https://example.com/schemas/schema.json", `{"$ref": "defs.json#/definitions/workflow"}`)
+		roundTripper.StubBody("https://example.com/schemas/defs.json", defs)
+		reader := content.NewReader(fs, &http.Client{Transport: roundTripper})
+		logger, _ := io.NewTestLogger()
+		return NewValidator(fs, NewSchemaCache(fs, reader, logger, context), NewBuildCache(fs, logger, context), context)
+	}
+
+	result := newValidator(`{"definitions": {"workflow": {}}}`).ValidateSchema(definition)
+	assert.True(t, result.Valid)
+
+	// the referenced schema changes, so the cached result shouldn't be used
+	context.RefreshSchemas = true
+	result = newValidator(`{"definitions": {"workflow": {"required": ["name"]}}}`).ValidateSchema(definition)
+	assert.False(t, result.Valid)
+	assert.Equal(t, []string{"(root): name is required"}, result.Errors)
+}
@@ -0,0 +1,100 @@
+package yamlutil
+
+import (
+	"bytes"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// workflowKeyOrder, jobKeyOrder and stepKeyOrder - the conventional order of keys in GitHub
+// workflows, as used in GitHub's documentation and starter workflows
+var (
+	workflowKeyOrder = []string{"name", "on", "permissions", "env", "defaults", "concurrency", "jobs"}
+	jobKeyOrder      = []string{"name", "needs", "if", "runs-on", "permissions", "environment", "concurrency",
+		"outputs", "env", "defaults", "strategy", "continue-on-error", "timeout-minutes", "container", "services",
+		"uses", "with", "secrets", "steps"}
+	stepKeyOrder = []string{"name", "id", "if", "uses", "with", "run", "shell", "working-directory", "env",
+		"continue-on-error", "timeout-minutes"}
+)
+
+// OrderWorkflowKeys - re-emits the workflow with the keys of the workflow, its jobs and their steps
+// in GitHub's conventional order (e.g. name, on, jobs). Keys with no conventional position follow
+// the others in their original order. Quoting styles and comments are preserved.
+func OrderWorkflowKeys(content string) (string, error) {
+	var document yaml.Node
+	err := yaml.Unmarshal([]byte(content), &document)
+	if err != nil {
+		return "", err
+	}
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		return content, nil
+	}
+
+	root := document.Content[0]
+	orderMappingKeys(root, workflowKeyOrder)
+	if jobs := findMappingValue(root, "jobs"); jobs != nil && jobs.Kind == yaml.MappingNode {
+		for i := 1; i < len(jobs.Content); i += 2 {
+			job := jobs.Content[i]
+			if job.Kind != yaml.MappingNode {
+				continue
+			}
+			orderMappingKeys(job, jobKeyOrder)
+			if steps := findMappingValue(job, "steps"); steps != nil && steps.Kind == yaml.SequenceNode {
+				for _, step := range steps.Content {
+					if step.Kind == yaml.MappingNode {
+						orderMappingKeys(step, stepKeyOrder)
+					}
+				}
+			}
+		}
+	}
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	err = encoder.Encode(&document)
+	if err != nil {
+		return "", err
+	}
+	err = encoder.Close()
+	return out.String(), err
+}
+
+// orderMappingKeys - sorts the key/value pairs of the mapping node so that the given keys come
+// first, in the given order
+func orderMappingKeys(node *yaml.Node, order []string) {
+	ranks := make(map[string]int)
+	for i, key := range order {
+		ranks[key] = i
+	}
+	rank := func(key *yaml.Node) int {
+		if r, ok := ranks[key.Value]; ok {
+			return r
+		}
+		return len(order)
+	}
+
+	pairs := [][2]*yaml.Node{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		pairs = append(pairs, [2]*yaml.Node{node.Content[i], node.Content[i+1]})
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return rank(pairs[i][0]) < rank(pairs[j][0])
+	})
+
+	content := make([]*yaml.Node, 0, len(node.Content))
+	for _, pair := range pairs {
+		content = append(content, pair[0], pair[1])
+	}
+	node.Content = content
+}
+
+func findMappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
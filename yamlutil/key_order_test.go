@@ -0,0 +1,54 @@
+package yamlutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderWorkflowKeys(t *testing.T) {
+	input := `"jobs":
+  "build":
+    "steps":
+    - "run": "npm test"
+      "name": "Test"
+      "custom": true
+      "if": "always()"
+    "runs-on": "ubuntu-latest"
+    "name": "Build"
+"name": "CI"
+"x-custom": 1
+"on": "push"
+`
+
+	result, err := OrderWorkflowKeys(input)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `"name": "CI"
+"on": "push"
+"jobs":
+  "build":
+    "name": "Build"
+    "runs-on": "ubuntu-latest"
+    "steps":
+      - "name": "Test"
+        "if": "always()"
+        "run": "npm test"
+        "custom": true
+"x-custom": 1
+`, result)
+}
+
+func TestOrderWorkflowKeysPreservesComments(t *testing.T) {
+	input := "jobs: {} # the jobs\n# the trigger\non: push\n"
+
+	result, err := OrderWorkflowKeys(input)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "# the trigger\non: push\njobs: {} # the jobs\n", result)
+}
+
+func TestOrderWorkflowKeysInvalidYaml(t *testing.T) {
+	_, err := OrderWorkflowKeys("foo: bar: baz")
+	assert.Error(t, err)
+}
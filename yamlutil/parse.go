@@ -0,0 +1,17 @@
+package yamlutil
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+// ParseYaml - parses the YAML document into a tree of maps, lists and scalars. Unlike YamlToJson
+// this follows YAML 1.2, so keys like `on` in GitHub workflows are parsed as strings rather than
+// booleans.
+func ParseYaml(input string) (interface{}, error) {
+	var document interface{}
+	err := yaml.Unmarshal([]byte(input), &document)
+	if err != nil {
+		return nil, err
+	}
+	return document, nil
+}
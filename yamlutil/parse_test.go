@@ -0,0 +1,28 @@
+package yamlutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseYaml(t *testing.T) {
+	result, err := ParseYaml("on: push\njobs:\n  test:\n    steps:\n    - run: echo 1\n")
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"on": "push",
+		"jobs": map[string]interface{}{
+			"test": map[string]interface{}{
+				"steps": []interface{}{
+					map[string]interface{}{"run": "echo 1"},
+				},
+			},
+		},
+	}, result)
+}
+
+func TestParseYamlInvalid(t *testing.T) {
+	_, err := ParseYaml("foo: bar: baz")
+	assert.Error(t, err)
+}
@@ -0,0 +1,59 @@
+package yamlutil
+
+import (
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RootPath - the path of the root node, as used by gojsonschema in validation errors
+const RootPath = "(root)"
+
+// Position - the (1 based) line and column of a node in a YAML document
+type Position struct {
+	Line int
+	Col  int
+}
+
+// FindPositions - returns the position of each node below the root of the YAML document, keyed by
+// its path in the dot separated form used by gojsonschema (e.g. "jobs.build.steps.0"). The
+// position of a map entry is the position of its key.
+func FindPositions(input string) (map[string]Position, error) {
+	var document yaml.Node
+	err := yaml.Unmarshal([]byte(input), &document)
+	if err != nil {
+		return nil, err
+	}
+
+	positions := make(map[string]Position)
+	if len(document.Content) > 0 {
+		findChildPositions(document.Content[0], "", positions)
+	}
+	return positions, nil
+}
+
+// JoinPath - returns the path for the given key of the node at the parent path
+func JoinPath(parent string, key string) string {
+	if parent == "" || parent == RootPath {
+		return key
+	}
+	return parent + "." + key
+}
+
+func findChildPositions(node *yaml.Node, path string, positions map[string]Position) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			childPath := JoinPath(path, key.Value)
+			positions[childPath] = Position{Line: key.Line, Col: key.Column}
+			findChildPositions(value, childPath, positions)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			childPath := JoinPath(path, strconv.Itoa(i))
+			positions[childPath] = Position{Line: item.Line, Col: item.Column}
+			findChildPositions(item, childPath, positions)
+		}
+	}
+}
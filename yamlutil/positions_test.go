@@ -0,0 +1,35 @@
+package yamlutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindPositions(t *testing.T) {
+	positions, err := FindPositions(`# comment
+"on": push
+jobs:
+  build:
+    steps:
+      - run: echo hello
+      - uses: actions/checkout@v2
+`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]Position{
+		"on":                      {Line: 2, Col: 1},
+		"jobs":                    {Line: 3, Col: 1},
+		"jobs.build":              {Line: 4, Col: 3},
+		"jobs.build.steps":        {Line: 5, Col: 5},
+		"jobs.build.steps.0":      {Line: 6, Col: 9},
+		"jobs.build.steps.0.run":  {Line: 6, Col: 9},
+		"jobs.build.steps.1":      {Line: 7, Col: 9},
+		"jobs.build.steps.1.uses": {Line: 7, Col: 9},
+	}, positions)
+}
+
+func TestFindPositionsInvalidYaml(t *testing.T) {
+	_, err := FindPositions("foo: bar: baz")
+	assert.Error(t, err)
+}